@@ -12,6 +12,15 @@ export const apiClient = axios.create({
   timeout: 10000,
 })
 
+// Добавляем токен администратора ко всем запросам, если заголовок не передан явно (см. authApi.checkPassword)
+apiClient.interceptors.request.use((config) => {
+  const token = localStorage.getItem('admin_token')
+  if (token && !config.headers.Authorization) {
+    config.headers.Authorization = `Bearer ${token}`
+  }
+  return config
+})
+
 // Интерфейсы для типизации
 export interface Quote {
   id: string
@@ -21,6 +30,7 @@ export interface Quote {
   is_liked?: boolean // Опциональное поле, так как в админке не используется
   created_at: string
   updated_at: string
+  version: number
 }
 
 export interface CreateQuoteRequest {
@@ -65,8 +75,10 @@ export const quotesApi = {
     return response.data
   },
 
-  update: async (id: string, data: UpdateQuoteRequest): Promise<Quote> => {
-    const response = await apiClient.put<Quote>(`/quotes/${id}`, data)
+  // version - версия цитаты, которую видел редактор; если цитату успели изменить, сервер вернет 412
+  update: async (id: string, data: UpdateQuoteRequest, version?: number): Promise<Quote> => {
+    const headers = version ? { 'If-Match': `"v${version}"` } : undefined
+    const response = await apiClient.put<Quote>(`/quotes/${id}`, data, { headers })
     return response.data
   },
 
@@ -79,3 +91,13 @@ export const quotesApi = {
   },
 }
 
+
+export const authApi = {
+  // Проверяет пароль администратора запросом к защищенному эндпоинту
+  // Пароль передается явно, потому что он еще не сохранен как токен
+  checkPassword: async (password: string): Promise<void> => {
+    await apiClient.get('/admin/session', {
+      headers: { Authorization: `Bearer ${password}` },
+    })
+  },
+}
@@ -4,11 +4,15 @@ import (
 	"log"
 	"os"
 
+	"quotes-backend/internal/cli"
 	"quotes-backend/internal/config"
 	"quotes-backend/internal/database"
 	"quotes-backend/internal/handlers"
+	"quotes-backend/internal/jobs"
 	"quotes-backend/internal/repository"
 	"quotes-backend/internal/router"
+	"quotes-backend/internal/typography"
+	"quotes-backend/internal/validation"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -38,14 +42,58 @@ func main() {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	// Инициализация репозитория
-	quoteRepo := repository.NewQuoteRepository(db)
+	// Инициализация репозиториев
+	quoteRepo := repository.NewQuoteRepository(db, cfg.DuplicateSimilarity)
+	authorRepo := repository.NewAuthorRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+
+	// Заполнение хешей для поиска дубликатов у цитат, созданных до их появления
+	if updated, err := quoteRepo.BackfillContentHashes(); err != nil {
+		log.Fatalf("Failed to backfill content hashes: %v", err)
+	} else if updated > 0 {
+		log.Printf("Backfilled content hashes for %d quotes", updated)
+	}
+
+	// Ограничения длины текста и автора для создаваемых и импортируемых цитат
+	limits := validation.Limits{MaxTextLength: cfg.MaxQuoteLength, MaxAuthorLength: cfg.MaxAuthorLength}
+
+	// Нормализация типографики включается через TYPOGRAPHY_ENABLED
+	var typo *typography.Options
+	if cfg.TypographyEnabled {
+		typo = &typography.Options{RestoreYo: cfg.TypographyRestoreYo}
+	}
+
+	// Если указана консольная команда, выполняем ее вместо запуска HTTP сервера
+	if len(os.Args) > 1 {
+		if err := cli.Run(cli.Deps{Config: cfg, Quotes: quoteRepo, Limits: limits, Typography: typo}, os.Args[1:]); err != nil {
+			log.Fatalf("Command failed: %v", err)
+		}
+		return
+	}
+
+	// Пароль администратора - единственный секрет административного API, пустой пароль не допускается
+	if cfg.AdminPassword == "" {
+		log.Fatalf("ADMIN_PASSWORD is not set")
+	}
+
+	// Запуск фоновой очистки корзины и устаревших ключей идемпотентности
+	jobs.StartTrashPurger(quoteRepo, cfg.TrashRetention)
+	jobs.StartIdempotencyPurger(idempotencyRepo)
 
 	// Инициализация обработчиков
-	quoteHandler := handlers.NewQuoteHandler(quoteRepo)
+	quoteHandler := handlers.NewQuoteHandler(quoteRepo, limits, typo)
+	authorHandler := handlers.NewAuthorHandler(authorRepo)
+	reportHandler := handlers.NewReportHandler(reportRepo, cfg.ReportHideThreshold)
+	shareHandler := handlers.NewShareHandler(quoteRepo, cfg.PublicBaseURL, cfg.ShareImageURL)
+	feedHandler := handlers.NewFeedHandler(quoteRepo, cfg.PublicBaseURL, cfg.DailyCalendarPastDays, cfg.DailyCalendarFutureDays)
 
 	// Настройка роутера
-	r := router.SetupRouter(quoteHandler, cfg)
+	r := router.SetupRouter(quoteHandler, authorHandler, reportHandler, feedHandler, shareHandler, idempotencyRepo, cfg)
+	// IP клиента берется из X-Forwarded-For только за доверенными прокси, иначе заголовок можно подделать
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	// Запуск сервера
 	port := os.Getenv("API_PORT")
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"quotes-backend/internal/config"
+	"quotes-backend/internal/repository"
+	"quotes-backend/internal/typography"
+	"quotes-backend/internal/validation"
+)
+
+// Deps содержит зависимости, доступные консольным командам
+type Deps struct {
+	Config *config.Config
+	Quotes repository.QuoteRepository
+	Limits validation.Limits // Ограничения длины полей для импортируемых и исправляемых цитат
+	// Typography - нормализация типографики импортируемых цитат (nil - выключена)
+	Typography *typography.Options
+}
+
+// command описывает консольную команду
+type command struct {
+	description string
+	run         func(deps Deps, args []string) error
+}
+
+// commands содержит все доступные консольные команды
+var commands = map[string]command{
+	"import":     {description: "Импорт цитат из CSV, JSON, NDJSON, fortune или Kindle (My Clippings.txt)", run: runImport},
+	"wikiquote":  {description: "Импорт цитат из XML дампа Викицитатника", run: runWikiquote},
+	"export":     {description: "Выгрузка цитат в CSV, JSON, NDJSON или fortune", run: runExport},
+	"typography": {description: "Нормализация типографики цитат (кавычки, тире, многоточия)", run: runTypography},
+}
+
+// Run выполняет консольную команду, указанную первым аргументом
+func Run(deps Deps, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage()
+		return nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		printUsage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	return cmd.run(deps, args[1:])
+}
+
+// printUsage выводит список доступных команд
+func printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Использование: main <команда> [параметры]")
+	fmt.Fprintln(os.Stderr, "Без команды запускается HTTP сервер.")
+	fmt.Fprintln(os.Stderr, "\nДоступные команды:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].description)
+	}
+	fmt.Fprintln(os.Stderr, "\nПараметры команды: main <команда> -h")
+}
+
+// openInput открывает файл для чтения; "-" означает стандартный ввод
+func openInput(path string) (*os.File, error) {
+	if path == "-" {
+		return os.Stdin, nil
+	}
+	return os.Open(path)
+}
+
+// joinFormats формирует список форматов для справки
+func joinFormats(formats ...string) string {
+	return strings.Join(formats, ", ")
+}
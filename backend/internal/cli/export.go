@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"quotes-backend/internal/exporter"
+	"quotes-backend/internal/models"
+)
+
+// runExport выгружает цитаты в файл или стандартный вывод
+// Пример: main export -format csv -include likes,timestamps -o quotes.csv
+func runExport(deps Deps, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", exporter.FormatJSON, "формат выгрузки: "+joinFormats(exporter.FormatCSV, exporter.FormatJSON, exporter.FormatNDJSON, exporter.FormatFortune))
+	include := fs.String("include", "", "дополнительные поля через запятую: likes, timestamps")
+	output := fs.String("o", "-", "файл для выгрузки (- для стандартного вывода); для fortune рядом создается индекс <файл>.dat")
+	var filter models.QuoteFilter
+	fs.StringVar(&filter.Search, "search", "", "поисковый запрос")
+	fs.StringVar(&filter.Author, "author", "", "фильтр по автору")
+	fs.StringVar(&filter.AttributionStatus, "attribution-status", "", "фильтр по статусу атрибуции")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if filter.AttributionStatus != "" && !models.IsValidAttributionStatus(filter.AttributionStatus) {
+		return fmt.Errorf("invalid attribution status %q", filter.AttributionStatus)
+	}
+
+	opts, err := exporter.ParseInclude(*include)
+	if err != nil {
+		return err
+	}
+
+	out, closeOutput, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	writer, err := exporter.NewWriter(*format, out, opts)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = deps.Quotes.Export(filter, func(quote *models.Quote) error {
+		count++
+		return writer.Write(quote)
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	// Для fortune рядом с файлом создается индекс, без которого fortune(6) не читает коллекцию
+	if fortune, ok := writer.(*exporter.FortuneWriter); ok && *output != "-" {
+		if err := writeFortuneIndex(fortune, *output+".dat"); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d quotes\n", count)
+	return nil
+}
+
+// writeFortuneIndex записывает индексный файл strfile(8) для выгруженного файла fortune
+func writeFortuneIndex(fortune *exporter.FortuneWriter, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := fortune.WriteIndex(file); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// openOutput открывает файл для записи; "-" означает стандартный вывод
+// Возвращаемая функция сбрасывает буфер и закрывает файл
+func openOutput(path string) (io.Writer, func(), error) {
+	if path == "-" {
+		w := bufio.NewWriter(os.Stdout)
+		return w, func() { w.Flush() }, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return file, func() { file.Close() }, nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"quotes-backend/internal/importer"
+)
+
+// runImport импортирует цитаты из файла
+// Пример: main import -format csv -mode partial -dry-run quotes.csv
+func runImport(deps Deps, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "формат файла: "+joinFormats(importer.FormatCSV, importer.FormatJSON, importer.FormatNDJSON, importer.FormatFortune, importer.FormatKindle)+" (по умолчанию по имени файла)")
+	mode := fs.String("mode", importer.ModeAtomic, "режим: atomic или partial")
+	dryRun := fs.Bool("dry-run", false, "только проверить файл и показать цитаты, которые будут созданы")
+	batchSize := fs.Int("batch-size", importer.DefaultBatchSize, "размер пачки для записи")
+	editor := fs.String("editor", "cli", "автор изменений для истории ревизий")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Использование: main import [параметры] <файл|->")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("import expects exactly one file")
+	}
+
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = importer.DetectFormat("", path)
+	}
+	if *format == "" {
+		return fmt.Errorf("unable to detect import format, use -format")
+	}
+
+	file, err := openInput(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	reader, err := importer.NewReader(*format, file)
+	if err != nil {
+		return err
+	}
+
+	summary, err := importer.Run(reader, deps.Quotes, importer.Options{
+		Format:     *format,
+		Mode:       *mode,
+		DryRun:     *dryRun,
+		BatchSize:  *batchSize,
+		Editor:     *editor,
+		Limits:     deps.Limits,
+		Typography: deps.Typography,
+	})
+	if summary != nil {
+		printSummary(summary)
+	}
+
+	return err
+}
+
+// printSummary выводит итоги импорта в формате JSON
+func printSummary(summary *importer.Summary) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(summary)
+}
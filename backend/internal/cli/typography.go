@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/repository"
+	"quotes-backend/internal/typography"
+)
+
+// runTypography нормализует типографику всех цитат, кроме находящихся в корзине
+// (включая черновики, запланированные, скрытые и ожидающие модерации)
+// Исправленные цитаты проверяются теми же ограничениями, что и при создании через API
+// Каждое изменение сохраняется как ревизия, поэтому его можно откатить через историю изменений
+// Пример: main typography -dry-run -restore-yo
+func runTypography(deps Deps, args []string) error {
+	fs := flag.NewFlagSet("typography", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "только показать цитаты, которые будут изменены")
+	restoreYo := fs.Bool("restore-yo", deps.Config.TypographyRestoreYo, "восстанавливать букву ё в однозначных словах")
+	editor := fs.String("editor", "typography", "автор изменений для истории ревизий")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := typography.Options{RestoreYo: *restoreYo}
+
+	// Цитаты сначала собираются, а затем обновляются: курсор выгрузки держит транзакцию открытой
+	var changed []*models.Quote
+	total, failed := 0, 0
+	err := deps.Quotes.ExportAll(func(quote *models.Quote) error {
+		total++
+		// Источник копируется, чтобы снимок сохранил исходные значения
+		if quote.Source != nil {
+			source := *quote.Source
+			quote.Source = &source
+		}
+		before := quote.Snapshot()
+		typography.Quote(quote, opts)
+		if err := deps.Limits.Quote(quote); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", quote.ID, err)
+			failed++
+			return nil
+		}
+		if !reflect.DeepEqual(quote.Snapshot(), before) {
+			changed = append(changed, quote)
+			if *dryRun {
+				fmt.Printf("%s\n  - %s\n  + %s\n", quote.ID, before.Text, quote.Text)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Fprintf(os.Stderr, "Checked %d quotes, %d would be changed, %d invalid\n", total, len(changed), failed)
+		if failed > 0 {
+			return fmt.Errorf("%d quotes failed validation", failed)
+		}
+		return nil
+	}
+
+	updated := 0
+	for _, quote := range changed {
+		if err := deps.Quotes.UpdateAny(quote.ID, quote, *editor); err != nil {
+			if errors.Is(err, repository.ErrQuoteModified) {
+				// Цитату изменили во время обработки - она будет нормализована при следующем запуске
+				err = errors.New("modified concurrently, skipped")
+			}
+			fmt.Fprintf(os.Stderr, "%s: %v\n", quote.ID, err)
+			failed++
+			continue
+		}
+		updated++
+	}
+
+	fmt.Fprintf(os.Stderr, "Checked %d quotes, updated %d, failed %d\n", total, updated, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d quotes were not updated", failed)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"compress/bzip2"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"quotes-backend/internal/importer"
+)
+
+// runWikiquote импортирует цитаты из XML дампа Викицитатника
+// Дамп читается потоково, поэтому подходит и для полных выгрузок (в том числе .xml.bz2)
+// Пример: main wikiquote -dry-run ruwikiquote-latest-pages-articles.xml.bz2
+func runWikiquote(deps Deps, args []string) error {
+	fs := flag.NewFlagSet("wikiquote", flag.ContinueOnError)
+	mode := fs.String("mode", importer.ModePartial, "режим: atomic или partial")
+	dryRun := fs.Bool("dry-run", false, "только разобрать дамп и показать цитаты, которые будут созданы")
+	batchSize := fs.Int("batch-size", importer.DefaultBatchSize, "размер пачки для записи")
+	editor := fs.String("editor", "wikiquote", "автор изменений для истории ревизий")
+	allPages := fs.Bool("all-pages", false, "импортировать все статьи, а не только страницы персоналий")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Использование: main wikiquote [параметры] <дамп.xml|дамп.xml.bz2|->")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("wikiquote expects exactly one dump file")
+	}
+
+	path := fs.Arg(0)
+	file, err := openInput(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var input io.Reader = file
+	if strings.HasSuffix(strings.ToLower(path), ".bz2") {
+		input = bzip2.NewReader(file)
+	}
+
+	reader := importer.NewWikiquoteReader(input, importer.WikiquoteOptions{AllPages: *allPages})
+
+	summary, err := importer.Run(reader, deps.Quotes, importer.Options{
+		Format:     importer.FormatWikiquote,
+		Mode:       *mode,
+		DryRun:     *dryRun,
+		BatchSize:  *batchSize,
+		Editor:     *editor,
+		Limits:     deps.Limits,
+		Typography: deps.Typography,
+	})
+	if summary != nil {
+		printSummary(summary)
+	}
+
+	return err
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config содержит конфигурацию приложения
@@ -14,6 +18,49 @@ type Config struct {
 	DBSSLMode  string
 	APIPort    string
 	CORSOrigin string
+
+	// AdminPassword используется как Bearer токен для административных эндпоинтов
+	// Значения по умолчанию нет: без него сервер не запускается
+	AdminPassword string
+	// TrashRetention - срок хранения цитат в корзине до автоматической очистки (0 - не очищать)
+	TrashRetention time.Duration
+	// DuplicateSimilarity - порог триграммной похожести текста (0..1), начиная с которого цитаты считаются дубликатами
+	DuplicateSimilarity float64
+	// SubmissionRateLimit - количество цитат, которые посетитель может предложить за SubmissionRateWindow (0 - без ограничения)
+	SubmissionRateLimit  int
+	SubmissionRateWindow time.Duration
+	// ReportHideThreshold - количество открытых жалоб, после которого цитата скрывается до решения администратора (0 - не скрывать)
+	ReportHideThreshold int
+	// ReportRateLimit - количество жалоб, которые посетитель может отправить за ReportRateWindow (0 - без ограничения)
+	ReportRateLimit  int
+	ReportRateWindow time.Duration
+	// TrustedProxies - адреса и подсети прокси, которым доверяются заголовки X-Forwarded-For и X-Real-IP
+	// Для остальных клиентов IP адресом считается адрес соединения
+	TrustedProxies []string
+	// IdempotencyTTL - срок хранения ответов на запросы с заголовком Idempotency-Key
+	IdempotencyTTL time.Duration
+	// Заголовки Cache-Control для публичных GET эндпоинтов: список цитат, цитата по ID, топы
+	// CacheControlPersonalized применяется к ответам с полем is_liked, которые нельзя хранить в общем кэше
+	CacheControlList         string
+	CacheControlQuote        string
+	CacheControlTop          string
+	CacheControlPersonalized string
+	// CacheControlFeed - заголовок Cache-Control для лент RSS и Atom
+	CacheControlFeed string
+	// PublicBaseURL - публичный адрес сайта (SPA) для ссылок в лентах, на страницах цитат и в карте сайта
+	PublicBaseURL string
+	// ShareImageURL - изображение для превью ссылок на цитаты в социальных сетях (пустое - без изображения)
+	ShareImageURL string
+	// DailyCalendarPastDays и DailyCalendarFutureDays - количество прошедших и будущих дней в календаре цитат дня (.ics)
+	DailyCalendarPastDays   int
+	DailyCalendarFutureDays int
+	// MaxQuoteLength и MaxAuthorLength - максимальная длина текста и автора цитаты в символах
+	MaxQuoteLength  int
+	MaxAuthorLength int
+	// TypographyEnabled включает нормализацию типографики (кавычки, тире, многоточия) при создании, изменении и импорте
+	// TypographyRestoreYo дополнительно восстанавливает букву ё в однозначных словах
+	TypographyEnabled   bool
+	TypographyRestoreYo bool
 }
 
 // Load загружает конфигурацию из переменных окружения
@@ -27,6 +74,39 @@ func Load() *Config {
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 		APIPort:    getEnv("API_PORT", "8080"),
 		CORSOrigin: getEnv("CORS_ORIGIN", "http://localhost:3000"),
+
+		AdminPassword:  os.Getenv("ADMIN_PASSWORD"),
+		TrashRetention: getDurationEnv("TRASH_RETENTION", 30*24*time.Hour),
+
+		DuplicateSimilarity: getFloatEnv("DUPLICATE_SIMILARITY", 0.8),
+
+		SubmissionRateLimit:  getIntEnv("SUBMISSION_RATE_LIMIT", 5),
+		SubmissionRateWindow: getDurationEnv("SUBMISSION_RATE_WINDOW", time.Hour),
+		ReportHideThreshold:  getIntEnv("REPORT_HIDE_THRESHOLD", 0),
+		ReportRateLimit:      getIntEnv("REPORT_RATE_LIMIT", 10),
+		ReportRateWindow:     getDurationEnv("REPORT_RATE_WINDOW", time.Hour),
+
+		TrustedProxies: getListEnv("TRUSTED_PROXIES", []string{"127.0.0.1", "::1", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}),
+
+		IdempotencyTTL: getDurationEnv("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		CacheControlList:         getEnv("CACHE_CONTROL_LIST", "public, max-age=60"),
+		CacheControlQuote:        getEnv("CACHE_CONTROL_QUOTE", "public, max-age=300"),
+		CacheControlTop:          getEnv("CACHE_CONTROL_TOP", "public, max-age=300"),
+		CacheControlPersonalized: getEnv("CACHE_CONTROL_PERSONALIZED", "private, no-cache"),
+		CacheControlFeed:         getEnv("CACHE_CONTROL_FEED", "public, max-age=900"),
+
+		PublicBaseURL: getEnv("PUBLIC_BASE_URL", "http://localhost:3000"),
+		ShareImageURL: getEnv("SHARE_IMAGE_URL", ""),
+
+		DailyCalendarPastDays:   getIntEnv("DAILY_CALENDAR_PAST_DAYS", 7),
+		DailyCalendarFutureDays: getIntEnv("DAILY_CALENDAR_FUTURE_DAYS", 30),
+
+		MaxQuoteLength:  getIntEnv("MAX_QUOTE_LENGTH", 2000),
+		MaxAuthorLength: getIntEnv("MAX_AUTHOR_LENGTH", 255),
+
+		TypographyEnabled:   getBoolEnv("TYPOGRAPHY_ENABLED", false),
+		TypographyRestoreYo: getBoolEnv("TYPOGRAPHY_RESTORE_YO", false),
 	}
 }
 
@@ -38,3 +118,88 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+
+// getDurationEnv получает длительность из переменной окружения (например, "720h")
+// При ошибке разбора возвращает значение по умолчанию
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration in %s=%q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return duration
+}
+
+// getFloatEnv получает число из переменной окружения
+// При ошибке разбора возвращает значение по умолчанию
+func getFloatEnv(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Invalid number in %s=%q, using default %v", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return number
+}
+
+// getIntEnv получает целое число из переменной окружения
+// При ошибке разбора возвращает значение по умолчанию
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid integer in %s=%q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return number
+}
+
+// getBoolEnv получает логическое значение из переменной окружения (true, false, 1, 0)
+// При ошибке разбора возвращает значение по умолчанию
+func getBoolEnv(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid boolean in %s=%q, using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return flag
+}
+
+// getListEnv получает список значений, разделенных запятыми, из переменной окружения
+// Пустые элементы пропускаются
+func getListEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
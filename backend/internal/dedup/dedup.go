@@ -0,0 +1,44 @@
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// Normalize приводит текст цитаты к виду для сравнения на дубликаты:
+// нижний регистр, "ё" заменяется на "е", знаки препинания и кавычки удаляются,
+// слова разделяются одним пробелом
+func Normalize(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	space := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == 'ё':
+			r = 'е'
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+		default:
+			// Любые другие символы считаются разделителями слов
+			space = b.Len() > 0
+			continue
+		}
+
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// ContentHash возвращает хеш нормализованного текста (см. Normalize)
+// Цитаты, отличающиеся только регистром, пунктуацией и пробелами, имеют одинаковый хеш
+func ContentHash(text string) string {
+	sum := sha256.Sum256([]byte(Normalize(text)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,265 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"quotes-backend/internal/models"
+)
+
+// Поддерживаемые форматы выгрузки
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// Дополнительные поля выгрузки, включаемые параметром include
+const (
+	IncludeLikes      = "likes"
+	IncludeTimestamps = "timestamps"
+)
+
+// Options определяет, какие дополнительные поля попадут в выгрузку
+type Options struct {
+	Likes      bool
+	Timestamps bool
+}
+
+// ParseInclude разбирает список дополнительных полей через запятую (например, "likes,timestamps")
+func ParseInclude(include string) (Options, error) {
+	var opts Options
+	for _, item := range strings.Split(include, ",") {
+		switch strings.TrimSpace(item) {
+		case "":
+		case IncludeLikes:
+			opts.Likes = true
+		case IncludeTimestamps:
+			opts.Timestamps = true
+		default:
+			return opts, fmt.Errorf("unsupported include %q", item)
+		}
+	}
+	return opts, nil
+}
+
+// Writer последовательно записывает цитаты в выходной поток
+// Close завершает документ (например, закрывает JSON массив) и сбрасывает буферы
+type Writer interface {
+	Write(quote *models.Quote) error
+	Close() error
+}
+
+// NewWriter создает потоковый writer для указанного формата
+func NewWriter(format string, w io.Writer, opts Options) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, opts), nil
+	case FormatJSON:
+		return &jsonWriter{w: bufio.NewWriter(w), opts: opts}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w), opts: opts}, nil
+	case FormatFortune:
+		return NewFortuneWriter(w), nil
+	case FormatFortuneArchive:
+		return newFortuneArchiveWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ContentType возвращает MIME тип выгрузки для формата
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatFortune:
+		return "text/plain; charset=utf-8"
+	case FormatFortuneArchive:
+		return "application/zip"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// FileName возвращает имя файла выгрузки для формата
+func FileName(format string) string {
+	switch format {
+	case FormatFortune:
+		return fortuneFileName
+	case FormatFortuneArchive:
+		return "quotes.zip"
+	default:
+		return "quotes." + format
+	}
+}
+
+// item представляет цитату в JSON выгрузке
+// Набор полей совместим с форматом импорта
+type item struct {
+	ID                string              `json:"id"`
+	Text              string              `json:"text"`
+	Author            string              `json:"author"`
+	Source            *models.QuoteSource `json:"source,omitempty"`
+	AttributionStatus string              `json:"attribution_status"`
+	AttributionNote   string              `json:"attribution_note,omitempty"`
+	LikesCount        *int                `json:"likes_count,omitempty"`
+	CreatedAt         *time.Time          `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time          `json:"updated_at,omitempty"`
+}
+
+// newItem создает элемент выгрузки с учетом дополнительных полей
+func newItem(quote *models.Quote, opts Options) item {
+	it := item{
+		ID:                quote.ID,
+		Text:              quote.Text,
+		Author:            quote.Author,
+		Source:            quote.Source,
+		AttributionStatus: quote.AttributionStatus,
+		AttributionNote:   quote.AttributionNote,
+	}
+	if opts.Likes {
+		it.LikesCount = &quote.LikesCount
+	}
+	if opts.Timestamps {
+		it.CreatedAt = &quote.CreatedAt
+		it.UpdatedAt = &quote.UpdatedAt
+	}
+	return it
+}
+
+// csvWriter записывает цитаты в CSV с заголовком, совместимым с импортом
+type csvWriter struct {
+	w      *csv.Writer
+	opts   Options
+	header bool
+}
+
+func newCSVWriter(w io.Writer, opts Options) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), opts: opts}
+}
+
+func (w *csvWriter) Write(quote *models.Quote) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	var source models.QuoteSource
+	if quote.Source != nil {
+		source = *quote.Source
+	}
+	year := ""
+	if source.Year != nil {
+		year = strconv.Itoa(*source.Year)
+	}
+
+	row := []string{
+		quote.ID, quote.Text, quote.Author,
+		source.Title, year, source.Page, source.URL, source.Context,
+		quote.AttributionStatus, quote.AttributionNote,
+	}
+	if w.opts.Likes {
+		row = append(row, strconv.Itoa(quote.LikesCount))
+	}
+	if w.opts.Timestamps {
+		row = append(row, quote.CreatedAt.Format(time.RFC3339), quote.UpdatedAt.Format(time.RFC3339))
+	}
+
+	return w.w.Write(row)
+}
+
+// writeHeader записывает заголовок перед первой строкой
+func (w *csvWriter) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+
+	columns := []string{
+		"id", "text", "author",
+		"source_title", "source_year", "source_page", "source_url", "source_context",
+		"attribution_status", "attribution_note",
+	}
+	if w.opts.Likes {
+		columns = append(columns, "likes_count")
+	}
+	if w.opts.Timestamps {
+		columns = append(columns, "created_at", "updated_at")
+	}
+
+	return w.w.Write(columns)
+}
+
+func (w *csvWriter) Close() error {
+	// Пустая выгрузка все равно содержит заголовок
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// jsonWriter записывает цитаты как JSON массив, не накапливая его в памяти
+type jsonWriter struct {
+	w     *bufio.Writer
+	opts  Options
+	count int
+}
+
+func (w *jsonWriter) Write(quote *models.Quote) error {
+	prefix := ",\n"
+	if w.count == 0 {
+		prefix = "[\n"
+	}
+	w.count++
+
+	data, err := json.Marshal(newItem(quote, w.opts))
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.WriteString(prefix); err != nil {
+		return err
+	}
+	_, err = w.w.Write(data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	closing := "\n]\n"
+	if w.count == 0 {
+		closing = "[]\n"
+	}
+	if _, err := w.w.WriteString(closing); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+// ndjsonWriter записывает по одной цитате на строку
+type ndjsonWriter struct {
+	w    *bufio.Writer
+	opts Options
+}
+
+func (w *ndjsonWriter) Write(quote *models.Quote) error {
+	data, err := json.Marshal(newItem(quote, w.opts))
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+	return w.w.WriteByte('\n')
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,170 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"quotes-backend/internal/models"
+)
+
+// Форматы выгрузки для fortune(6)
+const (
+	// FormatFortune - текстовый файл fortune: записи разделены строками "%"
+	FormatFortune = "fortune"
+	// FormatFortuneArchive - zip архив с файлом fortune и его индексом .dat в формате strfile(8)
+	FormatFortuneArchive = "fortune-zip"
+)
+
+// Параметры заголовка strfile(8)
+const (
+	strfileVersion = 2
+	strfileDelim   = '%'
+)
+
+// countingWriter подсчитывает количество записанных байт
+type countingWriter struct {
+	w *bufio.Writer
+	n uint32
+}
+
+func (w *countingWriter) WriteString(s string) error {
+	n, err := w.w.WriteString(s)
+	w.n += uint32(n)
+	return err
+}
+
+// FortuneWriter записывает цитаты в формате fortune(6) и запоминает смещения записей,
+// необходимые для построения индексного файла .dat
+type FortuneWriter struct {
+	w        *countingWriter
+	offsets  []uint32
+	longest  uint32
+	shortest uint32
+}
+
+// NewFortuneWriter создает writer файла fortune
+func NewFortuneWriter(w io.Writer) *FortuneWriter {
+	return &FortuneWriter{w: &countingWriter{w: bufio.NewWriter(w)}}
+}
+
+// Write записывает одну цитату, завершая ее строкой "%"
+func (w *FortuneWriter) Write(quote *models.Quote) error {
+	entry := formatFortune(quote)
+	length := uint32(len(entry))
+
+	w.offsets = append(w.offsets, w.w.n)
+	if length > w.longest {
+		w.longest = length
+	}
+	if w.shortest == 0 || length < w.shortest {
+		w.shortest = length
+	}
+
+	if err := w.w.WriteString(entry); err != nil {
+		return err
+	}
+	return w.w.WriteString("%\n")
+}
+
+// Close сбрасывает буфер
+func (w *FortuneWriter) Close() error {
+	return w.w.w.Flush()
+}
+
+// WriteIndex записывает индексный файл .dat в формате strfile(8)
+// Заголовок: версия, количество записей, длина самой длинной и самой короткой записи,
+// флаги и символ-разделитель; далее смещения начала каждой записи и конца файла
+// Все числа записываются в big-endian, как того требует fortune
+func (w *FortuneWriter) WriteIndex(out io.Writer) error {
+	header := []uint32{
+		strfileVersion,
+		uint32(len(w.offsets)),
+		w.longest,
+		w.shortest,
+		0, // Флаги: записи не отсортированы и не зашифрованы
+	}
+	if err := binary.Write(out, binary.BigEndian, header); err != nil {
+		return err
+	}
+	if _, err := out.Write([]byte{strfileDelim, 0, 0, 0}); err != nil {
+		return err
+	}
+
+	offsets := append(append([]uint32{}, w.offsets...), w.w.n)
+	return binary.Write(out, binary.BigEndian, offsets)
+}
+
+// Имена файлов коллекции в архиве FormatFortuneArchive
+const (
+	fortuneFileName  = "quotes"
+	fortuneIndexName = "quotes.dat"
+)
+
+// fortuneArchiveWriter записывает коллекцию fortune и ее индекс в zip архив за один проход
+// Записи zip сжимаются потоково, поэтому файл коллекции не хранится в памяти,
+// а смещения индекса всегда соответствуют записанному в архив файлу
+type fortuneArchiveWriter struct {
+	archive *zip.Writer
+	fortune *FortuneWriter
+}
+
+// newFortuneArchiveWriter создает writer архива и открывает в нем файл коллекции
+func newFortuneArchiveWriter(w io.Writer) (*fortuneArchiveWriter, error) {
+	archive := zip.NewWriter(w)
+	file, err := archive.Create(fortuneFileName)
+	if err != nil {
+		return nil, err
+	}
+	return &fortuneArchiveWriter{archive: archive, fortune: NewFortuneWriter(file)}, nil
+}
+
+func (w *fortuneArchiveWriter) Write(quote *models.Quote) error {
+	return w.fortune.Write(quote)
+}
+
+func (w *fortuneArchiveWriter) Close() error {
+	if err := w.fortune.Close(); err != nil {
+		return err
+	}
+	index, err := w.archive.Create(fortuneIndexName)
+	if err != nil {
+		return err
+	}
+	if err := w.fortune.WriteIndex(index); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+// formatFortune форматирует цитату как запись fortune:
+// текст и строка атрибуции "-- Автор" (с названием источника, если оно известно)
+func formatFortune(quote *models.Quote) string {
+	var b strings.Builder
+
+	for _, line := range strings.Split(strings.TrimSpace(quote.Text), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		// Строка из одного "%" была бы принята за разделитель записей
+		if line == "%" {
+			line = " %"
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	// Для цитат без автора строка атрибуции не пишется, как и в классических коллекциях
+	if quote.Author != models.UnknownAuthor {
+		b.WriteString("\t\t-- ")
+		b.WriteString(quote.Author)
+		if quote.Source != nil && quote.Source.Title != "" {
+			b.WriteString(`, "`)
+			b.WriteString(quote.Source.Title)
+			b.WriteString(`"`)
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
@@ -0,0 +1,277 @@
+// Package formats преобразует JSON ответы API в XML, простой текст и MessagePack
+// Ответы сначала формируются обработчиками в JSON, поэтому все форматы содержат одни и те же поля
+package formats
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Object - JSON объект с сохраненным порядком полей
+type Object struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// Get возвращает значение поля (nil, если поля нет)
+func (o *Object) Get(key string) interface{} {
+	return o.Values[key]
+}
+
+// String возвращает строковое значение поля
+func (o *Object) String(key string) (string, bool) {
+	s, ok := o.Values[key].(string)
+	return s, ok
+}
+
+// Decode разбирает JSON документ
+// Объекты возвращаются как *Object, целые числа - как int64, остальные числа - как float64
+func Decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := decodeValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+// decodeValue читает одно значение из потока токенов
+func decodeValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '[' {
+			values := []interface{}{}
+			for decoder.More() {
+				value, err := decodeValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+			_, err := decoder.Token()
+			return values, err
+		}
+
+		object := &Object{Values: make(map[string]interface{})}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyToken.(string)
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := object.Values[key]; !exists {
+				object.Keys = append(object.Keys, key)
+			}
+			object.Values[key] = value
+		}
+		_, err := decoder.Token()
+		return object, err
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n, nil
+		}
+		return t.Float64()
+	default:
+		return t, nil
+	}
+}
+
+// Plain заменяет *Object на map[string]interface{} для кодировщиков, не знающих о порядке полей
+func Plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *Object:
+		m := make(map[string]interface{}, len(v.Keys))
+		for _, key := range v.Keys {
+			m[key] = Plain(v.Values[key])
+		}
+		return m
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = Plain(item)
+		}
+		return values
+	default:
+		return value
+	}
+}
+
+// xmlRoot - имя корневого элемента XML ответа
+const xmlRoot = "response"
+
+// xmlItemNames - имена элементов массивов; для остальных массивов используется item
+var xmlItemNames = map[string]string{
+	"quotes":     "quote",
+	"duplicates": "duplicate",
+	"fields":     "error",
+	"aliases":    "alias",
+	"missing":    "id",
+	"liked":      "id",
+}
+
+// XML кодирует значение в XML
+// Поля объекта становятся вложенными элементами, элементы массива - повторяющимися элементами
+// (quotes -> quote); null - пустым элементом
+func XML(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&buf)
+	if err := writeXML(encoder, xmlRoot, value); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeXML записывает значение как элемент name
+func writeXML(encoder *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !isXMLName(name) {
+		// Ключи JSON, недопустимые как имя элемента, передаются атрибутом
+		start = xml.StartElement{
+			Name: xml.Name{Local: "entry"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+		}
+	}
+
+	switch v := value.(type) {
+	case *Object:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, key := range v.Keys {
+			if err := writeXML(encoder, key, v.Values[key]); err != nil {
+				return err
+			}
+		}
+		return encoder.EncodeToken(start.End())
+	case []interface{}:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		item, ok := xmlItemNames[name]
+		if !ok {
+			item = "item"
+		}
+		for _, element := range v {
+			if err := writeXML(encoder, item, element); err != nil {
+				return err
+			}
+		}
+		return encoder.EncodeToken(start.End())
+	case nil:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		return encoder.EncodeToken(start.End())
+	default:
+		return encoder.EncodeElement(fmt.Sprint(v), start)
+	}
+}
+
+// isXMLName проверяет, что строка может быть именем XML элемента
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Text представляет ответ простым текстом: цитата - строкой "текст — автор", список - цитатой на строку,
+// ошибка - сообщением и строками "поле: ошибка"
+// ok = false, если в ответе нет ни цитат, ни ошибки
+func Text(value interface{}) (text []byte, ok bool) {
+	var lines []string
+	if !appendText(&lines, value) {
+		return nil, false
+	}
+	if len(lines) == 0 {
+		return []byte{}, true
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), true
+}
+
+// appendText добавляет строки текстового представления значения
+func appendText(lines *[]string, value interface{}) bool {
+	switch v := value.(type) {
+	case *Object:
+		if message, ok := v.String("error"); ok {
+			*lines = append(*lines, message)
+			fields, _ := v.Get("fields").([]interface{})
+			for _, field := range fields {
+				if f, ok := field.(*Object); ok {
+					name, _ := f.String("field")
+					message, _ := f.String("message")
+					*lines = append(*lines, name+": "+message)
+				}
+			}
+			return true
+		}
+
+		if quotes, ok := v.Get("quotes").([]interface{}); ok {
+			// День календаря публикаций
+			if date, ok := v.String("date"); ok {
+				*lines = append(*lines, date)
+			}
+			for _, quote := range quotes {
+				if !appendText(lines, quote) {
+					return false
+				}
+			}
+			return true
+		}
+
+		text, hasText := v.String("text")
+		author, hasAuthor := v.String("author")
+		if !hasText && !hasAuthor {
+			return false
+		}
+		var parts []string
+		for _, part := range []string{text, author} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		*lines = append(*lines, strings.Join(parts, " — "))
+		return true
+	case []interface{}:
+		for _, item := range v {
+			if !appendText(lines, item) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
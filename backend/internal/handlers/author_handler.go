@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorHandler обрабатывает HTTP запросы для псевдонимов авторов
+type AuthorHandler struct {
+	repo repository.AuthorRepository
+}
+
+// NewAuthorHandler создает новый экземпляр обработчика авторов
+func NewAuthorHandler(repo repository.AuthorRepository) *AuthorHandler {
+	return &AuthorHandler{repo: repo}
+}
+
+// GetAliases возвращает псевдонимы авторов
+// @Summary Получить псевдонимы авторов
+// @Description Возвращает список псевдонимов, опционально для одного автора
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param author query string false "Основное имя автора"
+// @Success 200 {array} models.AuthorAlias
+// @Failure 500 {object} map[string]string
+// @Router /api/authors/aliases [get]
+func (h *AuthorHandler) GetAliases(c *gin.Context) {
+	aliases, err := h.repo.GetAliases(c.Query("author"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, aliases)
+}
+
+// CreateAlias создает псевдоним автора
+// @Summary Создать псевдоним автора
+// @Description Добавляет альтернативное написание имени автора, используемое при поиске
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param alias body models.CreateAuthorAliasRequest true "Данные псевдонима"
+// @Success 201 {object} models.AuthorAlias
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/authors/aliases [post]
+func (h *AuthorHandler) CreateAlias(c *gin.Context) {
+	var req models.CreateAuthorAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	alias := models.NewAuthorAlias(req)
+	if err := h.repo.CreateAlias(alias); err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, alias)
+}
+
+// DeleteAlias удаляет псевдоним автора
+// @Summary Удалить псевдоним автора
+// @Description Удаляет псевдоним автора по ID
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID псевдонима"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/admin/authors/aliases/{id} [delete]
+func (h *AuthorHandler) DeleteAlias(c *gin.Context) {
+	if err := h.repo.DeleteAlias(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
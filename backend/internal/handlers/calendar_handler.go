@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"quotes-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// calendarDateLayout - формат дат параметров и ответа календаря публикаций
+const calendarDateLayout = "2006-01-02"
+
+// defaultCalendarDays - период календаря публикаций по умолчанию
+const defaultCalendarDays = 30
+
+// GetCalendar возвращает календарь запланированных публикаций
+// @Summary Календарь публикаций
+// @Description Возвращает цитаты и черновики с временем публикации в интервале [from, to), сгруппированные по дням.
+// @Description По умолчанию - 30 дней, начиная с сегодняшнего
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Начальная дата (YYYY-MM-DD)"
+// @Param to query string false "Конечная дата, не включительно (YYYY-MM-DD)"
+// @Success 200 {array} models.ScheduledDay
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/calendar [get]
+func (h *QuoteHandler) GetCalendar(c *gin.Context) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.ParseInLocation(calendarDateLayout, raw, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from, expected YYYY-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, defaultCalendarDays)
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.ParseInLocation(calendarDateLayout, raw, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to, expected YYYY-MM-DD"})
+			return
+		}
+		to = parsed
+	}
+	if !to.After(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be after from"})
+		return
+	}
+
+	quotes, err := h.repo.GetScheduled(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Цитаты уже отсортированы по времени публикации, поэтому дни идут по порядку
+	days := []models.ScheduledDay{}
+	for _, quote := range quotes {
+		date := quote.PublishAt.Local().Format(calendarDateLayout)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, models.ScheduledDay{Date: date})
+		}
+		last := &days[len(days)-1]
+		last.Quotes = append(last.Quotes, quote.ToResponse(false))
+	}
+
+	h.respond(c, http.StatusOK, days)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDuplicates возвращает группы существующих дубликатов
+// @Summary Отчет о дубликатах
+// @Description Возвращает группы цитат с одинаковым (без учета регистра и пунктуации) или похожим текстом.
+// @Description Группа строится вокруг цитаты-якоря и содержит не более 20 ее дубликатов; группы отсортированы по ID якоря.
+// @Description Первой в группе идет самая популярная цитата - предлагаемая цель объединения
+// @Tags duplicates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/duplicates [get]
+func (h *QuoteHandler) GetDuplicates(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	clusters, total, err := h.repo.GetDuplicateClusters(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]models.DuplicateClusterResponse, 0, len(clusters))
+	for _, cluster := range clusters {
+		responses = append(responses, models.DuplicateClusterResponse{Quotes: duplicateResponses(cluster)})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"clusters":    responses,
+		"total":       total,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_pages": repository.CalculateTotalPages(total, pageSize),
+	})
+}
+
+// MergeDuplicates объединяет дубликаты в одну цитату
+// @Summary Объединить дубликаты
+// @Description Перемещает цитаты source_ids в корзину и переносит их лайки на цитату target_id.
+// @Description Повторные лайки одного пользователя не переносятся. Целью может быть только опубликованная цитата или черновик
+// @Description (цитата на модерации возвращает 400)
+// @Tags duplicates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.MergeQuotesRequest true "Цель и объединяемые цитаты"
+// @Success 200 {object} models.QuoteResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/admin/duplicates/merge [post]
+func (h *QuoteHandler) MergeDuplicates(c *gin.Context) {
+	var req models.MergeQuotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.Merge(req.TargetID, req.SourceIDs); err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "quote not found":
+			status = http.StatusNotFound
+		case "target quote cannot be merged into itself", "target quote must be published or a draft":
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.repo.GetEditable(req.TargetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respond(c, http.StatusOK, quote.ToResponse(false))
+}
+
+// visibleDuplicates оставляет только дубликаты, видимые посетителям
+func visibleDuplicates(duplicates []models.DuplicateQuote) []models.DuplicateQuote {
+	visible := make([]models.DuplicateQuote, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		if duplicate.Visible {
+			visible = append(visible, duplicate)
+		}
+	}
+	return visible
+}
+
+// duplicateResponses преобразует дубликаты в ответы API
+func duplicateResponses(duplicates []models.DuplicateQuote) []models.DuplicateQuoteResponse {
+	responses := make([]models.DuplicateQuoteResponse, len(duplicates))
+	for i, duplicate := range duplicates {
+		responses[i] = duplicate.ToResponse()
+	}
+	return responses
+}
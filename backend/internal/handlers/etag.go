@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"quotes-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// quoteETag возвращает ETag цитаты, основанный на ее версии
+func quoteETag(quote *models.Quote) string {
+	return fmt.Sprintf(`"v%d"`, quote.Version)
+}
+
+// parseIfMatch разбирает заголовок If-Match
+// Возвращает ожидаемую версию цитаты (0 - заголовок не передан или равен "*")
+// ok = false, если заголовок не содержит ETag цитаты - такой запрос не может совпасть с текущей версией
+func parseIfMatch(c *gin.Context) (version int, ok bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	// Поддерживается один ETag; слабые ETag (W/) для If-Match не подходят
+	if strings.HasPrefix(header, "W/") || strings.Contains(header, ",") {
+		return 0, false
+	}
+
+	value, found := strings.CutPrefix(strings.Trim(header, `"`), "v")
+	if !found {
+		return 0, false
+	}
+	// ETag ответа GET содержит также хеш тела (см. middleware.HTTPCache): "v3-<хеш>"
+	value, _, _ = strings.Cut(value, "-")
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"quotes-backend/internal/exporter"
+	"quotes-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Export выгружает все цитаты потоком
+// @Summary Выгрузить цитаты
+// @Description Потоково выгружает все цитаты в CSV, JSON, NDJSON или fortune(6). Фильтры совпадают с GET /api/quotes.
+// @Description format=fortune-zip выгружает zip архив с коллекцией fortune (quotes) и ее индексом quotes.dat, построенными за один проход
+// @Tags export
+// @Produce text/csv,application/json,application/x-ndjson,text/plain,application/zip
+// @Security BearerAuth
+// @Param format query string false "Формат выгрузки (csv, json, ndjson, fortune, fortune-zip)" default(json)
+// @Param include query string false "Дополнительные поля через запятую: likes, timestamps"
+// @Param search query string false "Поисковый запрос"
+// @Param author query string false "Фильтр по автору"
+// @Param attribution_status query string false "Фильтр по статусу атрибуции"
+// @Param has_source query bool false "Фильтр по наличию источника"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/admin/export [get]
+func (h *QuoteHandler) Export(c *gin.Context) {
+	filter, err := parseQuoteFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts, err := exporter.ParseInclude(c.Query("include"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := c.DefaultQuery("format", exporter.FormatJSON)
+	writer, err := exporter.NewWriter(format, c.Writer, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", exporter.ContentType(format))
+	c.Header("Content-Disposition", `attachment; filename="`+exporter.FileName(format)+`"`)
+	c.Status(http.StatusOK)
+
+	// После начала выгрузки статус ответа изменить нельзя, поэтому ошибки только логируются
+	err = h.repo.Export(filter, func(quote *models.Quote) error {
+		return writer.Write(quote)
+	})
+	if err != nil {
+		log.Printf("Export failed: %v", err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("Export failed: %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"quotes-backend/internal/feed"
+	"quotes-backend/internal/ical"
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Количество записей в ленте по умолчанию и максимальное
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+)
+
+// feedTitleLength - максимальная длина заголовка записи в символах
+const feedTitleLength = 80
+
+// dailyUIDSuffix - постоянная часть UID событий календаря цитат дня
+// Не зависит от адреса сайта, чтобы после переезда клиенты обновляли события, а не дублировали их
+const dailyUIDSuffix = "@daily.quotes-backend"
+
+// dailyRefresh - рекомендуемый клиентам интервал обновления календаря цитат дня
+const dailyRefresh = 12 * time.Hour
+
+// FeedHandler формирует ленты цитат в форматах RSS и Atom и календарь цитат дня
+type FeedHandler struct {
+	repo repository.QuoteRepository
+	// baseURL - публичный адрес сайта для ссылок в лентах (без завершающего /)
+	baseURL string
+	// dailyPastDays и dailyFutureDays - количество прошедших и будущих дней в календаре цитат дня
+	dailyPastDays   int
+	dailyFutureDays int
+}
+
+// NewFeedHandler создает новый экземпляр обработчика лент
+func NewFeedHandler(repo repository.QuoteRepository, baseURL string, dailyPastDays, dailyFutureDays int) *FeedHandler {
+	return &FeedHandler{
+		repo:            repo,
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+		dailyPastDays:   dailyPastDays,
+		dailyFutureDays: dailyFutureDays,
+	}
+}
+
+// GetLatestRSS возвращает ленту новых цитат в формате RSS
+// @Summary Лента новых цитат (RSS)
+// @Description Последние опубликованные цитаты, новые первыми по времени публикации. Поддерживает фильтры списка цитат
+// @Tags feeds
+// @Produce application/rss+xml
+// @Param limit query int false "Количество записей (не больше 100)" default(20)
+// @Param author query string false "Фильтр по автору (учитывает псевдонимы и транслитерацию)"
+// @Param search query string false "Поисковый запрос"
+// @Success 200 {string} string "RSS 2.0"
+// @Header 200 {string} ETag "Хеш ленты"
+// @Header 200 {string} Last-Modified "Время последнего изменения записей ленты"
+// @Failure 304 {string} string "Лента не изменилась"
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /feeds/quotes.rss [get]
+func (h *FeedHandler) GetLatestRSS(c *gin.Context) {
+	h.latest(c, feed.RSS, feed.RSSContentType)
+}
+
+// GetLatestAtom возвращает ленту новых цитат в формате Atom
+// @Summary Лента новых цитат (Atom)
+// @Description Последние опубликованные цитаты, новые первыми по времени публикации. Поддерживает фильтры списка цитат
+// @Tags feeds
+// @Produce application/atom+xml
+// @Param limit query int false "Количество записей (не больше 100)" default(20)
+// @Param author query string false "Фильтр по автору (учитывает псевдонимы и транслитерацию)"
+// @Param search query string false "Поисковый запрос"
+// @Success 200 {string} string "Atom"
+// @Header 200 {string} ETag "Хеш ленты"
+// @Header 200 {string} Last-Modified "Время последнего изменения записей ленты"
+// @Failure 304 {string} string "Лента не изменилась"
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /feeds/quotes.atom [get]
+func (h *FeedHandler) GetLatestAtom(c *gin.Context) {
+	h.latest(c, feed.Atom, feed.AtomContentType)
+}
+
+// GetTopWeeklyRSS возвращает ленту лучших цитат недели в формате RSS
+// @Summary Лента лучших цитат недели (RSS)
+// @Description Цитаты за последние 7 дней, отсортированные по количеству лайков
+// @Tags feeds
+// @Produce application/rss+xml
+// @Param limit query int false "Количество записей (не больше 100)" default(20)
+// @Success 200 {string} string "RSS 2.0"
+// @Header 200 {string} ETag "Хеш ленты"
+// @Failure 304 {string} string "Лента не изменилась"
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /feeds/top-weekly.rss [get]
+func (h *FeedHandler) GetTopWeeklyRSS(c *gin.Context) {
+	h.topWeekly(c, feed.RSS, feed.RSSContentType)
+}
+
+// GetTopWeeklyAtom возвращает ленту лучших цитат недели в формате Atom
+// @Summary Лента лучших цитат недели (Atom)
+// @Description Цитаты за последние 7 дней, отсортированные по количеству лайков
+// @Tags feeds
+// @Produce application/atom+xml
+// @Param limit query int false "Количество записей (не больше 100)" default(20)
+// @Success 200 {string} string "Atom"
+// @Header 200 {string} ETag "Хеш ленты"
+// @Failure 304 {string} string "Лента не изменилась"
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /feeds/top-weekly.atom [get]
+func (h *FeedHandler) GetTopWeeklyAtom(c *gin.Context) {
+	h.topWeekly(c, feed.Atom, feed.AtomContentType)
+}
+
+// latest отправляет ленту новых цитат в указанном формате
+func (h *FeedHandler) latest(c *gin.Context, encode func(*feed.Feed) ([]byte, error), contentType string) {
+	limit, ok := parseFeedLimit(c)
+	if !ok {
+		return
+	}
+	if c.Query("tag") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tag filter is not supported: quotes have no tags"})
+		return
+	}
+	filter, err := parseQuoteFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quotes, err := h.repo.GetLatestList(limit, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	title := "Цитаты: новые"
+	if filter.Author != "" {
+		title += " — " + filter.Author
+	}
+	f := h.newFeed(c, title, "Новые цитаты", quotes)
+
+	// Записи упорядочены по времени публикации, а время изменения записи не раньше ее публикации,
+	// поэтому время изменения ленты меняется и при появлении новых записей, и при изменении существующих
+	c.Header("Last-Modified", f.Updated().UTC().Format(http.TimeFormat))
+	h.render(c, f, encode, contentType)
+}
+
+// topWeekly отправляет ленту лучших цитат недели в указанном формате
+// Last-Modified не устанавливается: порядок записей меняется с лайками, не изменяя сами цитаты
+func (h *FeedHandler) topWeekly(c *gin.Context, encode func(*feed.Feed) ([]byte, error), contentType string) {
+	limit, ok := parseFeedLimit(c)
+	if !ok {
+		return
+	}
+
+	quotes, err := h.repo.GetTopWeeklyList(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.render(c, h.newFeed(c, "Цитаты: лучшие за неделю", "Цитаты недели с наибольшим количеством лайков", quotes), encode, contentType)
+}
+
+// render кодирует ленту и отправляет ее клиенту
+func (h *FeedHandler) render(c *gin.Context, f *feed.Feed, encode func(*feed.Feed) ([]byte, error), contentType string) {
+	data, err := encode(f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// newFeed собирает ленту из цитат
+// Идентификатор ленты - ее адрес с параметрами, чтобы ленты с разными фильтрами различались
+func (h *FeedHandler) newFeed(c *gin.Context, title, subtitle string, quotes []models.Quote) *feed.Feed {
+	selfURL := h.baseURL + c.Request.URL.RequestURI()
+	f := &feed.Feed{
+		ID:       selfURL,
+		Title:    title,
+		Subtitle: subtitle,
+		Link:     h.baseURL + "/",
+		SelfURL:  selfURL,
+		Entries:  make([]feed.Entry, len(quotes)),
+	}
+	for i := range quotes {
+		f.Entries[i] = h.entry(&quotes[i])
+	}
+	return f
+}
+
+// entry преобразует цитату в запись ленты
+// GUID записи (urn:uuid:<id>) не зависит от адреса сайта, поэтому не меняется при переезде
+// Запись считается измененной не раньше публикации: одобрение и отложенная публикация не меняют updated_at
+func (h *FeedHandler) entry(quote *models.Quote) feed.Entry {
+	published := quote.PublishedAt()
+	updated := quote.UpdatedAt
+	if published.After(updated) {
+		updated = published
+	}
+
+	content := quote.Text + "\n\n— " + quote.Author
+	if quote.Source != nil && quote.Source.Title != "" {
+		content += ", «" + quote.Source.Title + "»"
+	}
+
+	return feed.Entry{
+		ID:        "urn:uuid:" + quote.ID,
+		Title:     truncate(quote.Text, feedTitleLength),
+		Link:      shareURL(h.baseURL, quote.ID),
+		Author:    quote.Author,
+		Content:   content,
+		Published: published.UTC(),
+		Updated:   updated.UTC(),
+	}
+}
+
+// GetDailyCalendar возвращает календарь цитат дня
+// @Summary Календарь цитат дня (iCalendar)
+// @Description Одно событие на целый день для каждой даты от DAILY_CALENDAR_PAST_DAYS дней назад до DAILY_CALENDAR_FUTURE_DAYS
+// @Description дней вперед. Цитата закрепляется за датой при первом запросе; UID события зависит только от даты,
+// @Description поэтому клиенты обновляют события при изменении цитаты, а не дублируют их
+// @Tags feeds
+// @Produce text/calendar
+// @Success 200 {string} string "iCalendar (RFC 5545)"
+// @Header 200 {string} ETag "Хеш календаря"
+// @Failure 304 {string} string "Календарь не изменился"
+// @Failure 500 {object} map[string]string
+// @Router /feeds/quotes-of-the-day.ics [get]
+func (h *FeedHandler) GetDailyCalendar(c *gin.Context) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	daily, err := h.repo.GetDailyQuotes(today.AddDate(0, 0, -h.dailyPastDays), today.AddDate(0, 0, h.dailyFutureDays))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	cal := &ical.Calendar{
+		ProdID:  "-//quotes-backend//Quotes of the day//RU",
+		Name:    "Цитата дня",
+		Refresh: dailyRefresh,
+		Events:  make([]ical.Event, len(daily)),
+	}
+	for i, d := range daily {
+		// Событие меняется и при изменении цитаты, и при замене цитаты дня
+		modified := d.Quote.UpdatedAt
+		if d.AssignedAt.After(modified) {
+			modified = d.AssignedAt
+		}
+		cal.Events[i] = ical.Event{
+			UID:         d.Date.Format("20060102") + dailyUIDSuffix,
+			Date:        d.Date,
+			Summary:     truncate(d.Quote.Text, feedTitleLength) + " — " + d.Quote.Author,
+			Description: d.Quote.Text + "\n\n— " + d.Quote.Author,
+			URL:         shareURL(h.baseURL, d.Quote.ID),
+			Modified:    modified,
+		}
+	}
+
+	c.Data(http.StatusOK, ical.ContentType, ical.Encode(cal))
+}
+
+// parseFeedLimit разбирает параметр limit; при ошибке отправляет 400
+func parseFeedLimit(c *gin.Context) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return defaultFeedLimit, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > maxFeedLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxFeedLimit)})
+		return 0, false
+	}
+	return limit, true
+}
+
+// truncate обрезает текст до max символов по границе слова, добавляя многоточие
+func truncate(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	cut := string(runes[:max])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:—-") + "…"
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"quotes-backend/internal/importer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize ограничивает размер загружаемого файла импорта
+const maxImportSize = 100 << 20
+
+// Import импортирует цитаты из файла
+// @Summary Импортировать цитаты
+// @Description Потоково импортирует цитаты из CSV, JSON массива, NDJSON, файла fortune(6) или Kindle "My Clippings.txt". Файл передается телом запроса или полем file в multipart/form-data
+// @Tags import
+// @Accept text/csv,application/json,application/x-ndjson,text/plain,multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "Формат файла (csv, json, ndjson, fortune, kindle); по умолчанию определяется по Content-Type или имени файла"
+// @Param mode query string false "Режим: atomic (все или ничего) или partial (с отчетом об ошибочных строках)" default(atomic)
+// @Param dry_run query bool false "Только проверить файл и вернуть предпросмотр цитат, ничего не сохраняя"
+// @Param batch_size query int false "Размер пачки для записи" default(500)
+// @Success 200 {object} importer.Summary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} importer.Summary
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/import [post]
+func (h *QuoteHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	body, filename, err := importBody(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer body.Close()
+
+	format := c.Query("format")
+	if format == "" {
+		format = importer.DetectFormat(c.ContentType(), filename)
+	}
+	if format == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unable to detect import format, use the format parameter"})
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	batchSize, _ := strconv.Atoi(c.Query("batch_size"))
+
+	reader, err := importer.NewReader(format, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	summary, err := importer.Run(reader, h.repo, importer.Options{
+		Format:     format,
+		Mode:       c.DefaultQuery("mode", importer.ModeAtomic),
+		DryRun:     dryRun,
+		BatchSize:  batchSize,
+		Editor:     getEditor(c),
+		Limits:     h.limits,
+		Typography: h.typography,
+	})
+	switch {
+	case errors.Is(err, importer.ErrImportRolledBack):
+		c.JSON(http.StatusUnprocessableEntity, summary)
+		return
+	case err != nil && summary == nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "summary": summary})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// importBody возвращает содержимое файла импорта и его имя
+// Поддерживаются multipart/form-data (поле file) и передача файла телом запроса
+func importBody(c *gin.Context) (io.ReadCloser, string, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		return c.Request.Body, "", nil
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return nil, "", errors.New("multipart request must contain a file field")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return file, fileHeader.Filename, nil
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetModerationQueue возвращает очередь модерации
+// @Summary Получить очередь модерации
+// @Description Возвращает предложенные посетителями цитаты с пагинацией, начиная с самых старых.
+// @Description Параметр status позволяет просмотреть уже одобренные или отклоненные цитаты, а также черновики (draft)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Статус (pending, approved, rejected, draft)" default(pending)
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10)
+// @Success 200 {object} models.PaginatedQuotesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/moderation [get]
+func (h *QuoteHandler) GetModerationQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	status := c.DefaultQuery("status", models.StatusPending)
+
+	if !models.IsValidStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	quotes, total, err := h.repo.GetModerationQueue(page, pageSize, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]models.QuoteResponse, len(quotes))
+	for i, quote := range quotes {
+		responses[i] = quote.ToResponse(false)
+	}
+
+	h.respond(c, http.StatusOK, models.PaginatedQuotesResponse{
+		Quotes:     responses,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: repository.CalculateTotalPages(total, pageSize),
+	})
+}
+
+// ApproveQuote одобряет предложенную цитату
+// @Summary Одобрить цитату
+// @Description Публикует цитату из очереди модерации. Тело запроса необязательно:
+// @Description переданные поля исправляются перед публикацией и сохраняются в истории ревизий
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID цитаты"
+// @Param quote body models.UpdateQuoteRequest false "Исправления перед публикацией"
+// @Success 200 {object} models.QuoteResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/moderation/{id}/approve [post]
+func (h *QuoteHandler) ApproveQuote(c *gin.Context) {
+	var req models.UpdateQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondBindingError(c, err)
+		return
+	}
+
+	quote, err := h.repo.GetPending(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	applyUpdate(quote, req)
+	if err := h.normalize(quote); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	quote.Status = models.StatusApproved
+	quote.RejectionReason = ""
+
+	h.moderate(c, quote)
+}
+
+// RejectQuote отклоняет предложенную цитату
+// @Summary Отклонить цитату
+// @Description Отклоняет цитату из очереди модерации с указанием причины. Отклоненная цитата не публикуется
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID цитаты"
+// @Param request body models.RejectQuoteRequest true "Причина отклонения"
+// @Success 200 {object} models.QuoteResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/moderation/{id}/reject [post]
+func (h *QuoteHandler) RejectQuote(c *gin.Context) {
+	var req models.RejectQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.repo.GetPending(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote.Status = models.StatusRejected
+	quote.RejectionReason = req.Reason
+
+	h.moderate(c, quote)
+}
+
+// moderate сохраняет решение модератора и возвращает цитату
+func (h *QuoteHandler) moderate(c *gin.Context, quote *models.Quote) {
+	if err := h.repo.Moderate(quote.ID, quote, getEditor(c)); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "quote not found" {
+			// Цитата могла быть обработана другим модератором
+			status = http.StatusNotFound
+		} else if errors.Is(err, repository.ErrQuoteModified) {
+			// Автор или другой модератор изменил цитату во время проверки
+			status = http.StatusPreconditionFailed
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("ETag", quoteETag(quote))
+	h.respond(c, http.StatusOK, quote.ToResponse(false))
+}
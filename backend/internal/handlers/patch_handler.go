@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"quotes-backend/internal/jsonpatch"
+	"quotes-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxPatchSize - максимальный размер документа изменений
+const maxPatchSize = 1 << 20
+
+// Patch частично обновляет цитату
+// @Summary Частично обновить цитату
+// @Description Применяет JSON Merge Patch (application/merge-patch+json) или JSON Patch (application/json-patch+json)
+// @Description к редактируемым полям цитаты. В Merge Patch null очищает поле: {"source": null} удаляет источник.
+// @Description Результат проверяется так же, как при создании; изменение сохраняется в истории ревизий
+// @Tags quotes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID цитаты"
+// @Param If-Match header string false "ETag цитаты, полученный при чтении; при несовпадении возвращается 412"
+// @Param patch body models.QuoteDocument true "Изменяемые поля (Merge Patch) или список операций (JSON Patch)"
+// @Success 200 {object} models.QuoteResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/quotes/{id} [patch]
+func (h *QuoteHandler) Patch(c *gin.Context) {
+	id := c.Param("id")
+
+	var apply func(doc, patch []byte) ([]byte, error)
+	switch c.ContentType() {
+	case jsonpatch.MergePatchContentType, binding.MIMEJSON:
+		apply = jsonpatch.MergePatch
+	case jsonpatch.JSONPatchContentType:
+		apply = jsonpatch.Apply
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be " + jsonpatch.MergePatchContentType + " or " + jsonpatch.JSONPatchContentType})
+		return
+	}
+
+	patch, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPatchSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+		return
+	}
+	if len(patch) > maxPatchSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large"})
+		return
+	}
+
+	quote, ok := h.loadForUpdate(c, id)
+	if !ok {
+		return
+	}
+
+	original := quote.Document()
+	doc, err := json.Marshal(original)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	patched, err := apply(doc, patch)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Результат должен остаться корректной цитатой: неизвестные поля (например, likes_count) не допускаются
+	var result models.QuoteDocument
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if result.AttributionStatus == "" {
+		// Очистка статуса атрибуции возвращает значение по умолчанию
+		result.AttributionStatus = models.AttributionUnknown
+	}
+	if result.Source.IsEmpty() {
+		result.Source = nil
+	}
+	if err := binding.Validator.ValidateStruct(&result); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	quote.ApplyDocument(result)
+	if err := h.normalize(quote); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	h.saveUpdate(c, id, quote)
+}
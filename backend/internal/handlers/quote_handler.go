@@ -1,24 +1,64 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"quotes-backend/internal/middleware"
 	"quotes-backend/internal/models"
 	"quotes-backend/internal/repository"
+	"quotes-backend/internal/typography"
+	"quotes-backend/internal/validation"
 
 	"github.com/gin-gonic/gin"
 )
 
 // QuoteHandler обрабатывает HTTP запросы для цитат
 type QuoteHandler struct {
-	repo repository.QuoteRepository
+	repo       repository.QuoteRepository
+	limits     validation.Limits
+	typography *typography.Options
 }
 
 // NewQuoteHandler создает новый экземпляр обработчика
-func NewQuoteHandler(repo repository.QuoteRepository) *QuoteHandler {
-	return &QuoteHandler{repo: repo}
+// limits ограничивает длину текста и автора при создании, изменении и импорте цитат
+// typography включает нормализацию типографики этих цитат (nil - не изменять)
+func NewQuoteHandler(repo repository.QuoteRepository, limits validation.Limits, typo *typography.Options) *QuoteHandler {
+	return &QuoteHandler{repo: repo, limits: limits, typography: typo}
+}
+
+// normalize приводит поля цитаты к единому виду и проверяет их перед сохранением
+func (h *QuoteHandler) normalize(quote *models.Quote) error {
+	if h.typography != nil {
+		typography.Quote(quote, *h.typography)
+	}
+	return h.limits.Quote(quote)
+}
+
+// respondBindingError отправляет ошибку разбора тела запроса
+// Ошибки проверки полей возвращаются списком с указанием поля
+func respondBindingError(c *gin.Context, err error) {
+	if fields, ok := validation.FromBinding(err); ok {
+		respondValidationError(c, fields)
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// respondValidationError отправляет 400 с ошибками проверки полей (см. validation.Errors)
+func respondValidationError(c *gin.Context, err error) {
+	var fields validation.Errors
+	if !errors.As(err, &fields) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+		Error:  "validation failed",
+		Fields: fields,
+	})
 }
 
 // getUserIP получает IP адрес пользователя из запроса
@@ -34,13 +74,134 @@ func getUserIP(c *gin.Context) string {
 	return userIP
 }
 
+// Авторы изменений в истории ревизий, если заголовок X-Editor не передан
+const (
+	editorAdmin     = "admin"
+	editorAnonymous = "anonymous"
+)
+
+// getEditor возвращает идентификатор автора изменения для истории ревизий
+// История ревизий публична, поэтому IP адрес посетителя в нее не записывается
+// Заголовку X-Editor доверяется только в запросах администратора
+func getEditor(c *gin.Context) string {
+	if !middleware.IsAdmin(c) {
+		return editorAnonymous
+	}
+	if editor := strings.TrimSpace(c.GetHeader("X-Editor")); editor != "" {
+		return editor
+	}
+	return editorAdmin
+}
+
+// isLiked проверяет, лайкнул ли текущий пользователь цитату
+// Для неперсонализированных ответов (см. middleware.Personalized) всегда возвращает false
+func (h *QuoteHandler) isLiked(c *gin.Context, id string) bool {
+	if !middleware.Personalized(c) {
+		return false
+	}
+	isLiked, _ := h.repo.IsLiked(id, getUserIP(c))
+	return isLiked
+}
+
+// likedMap возвращает цитаты из ids, лайкнутые текущим пользователем
+// Для неперсонализированных ответов (см. middleware.Personalized) возвращает пустой набор
+func (h *QuoteHandler) likedMap(c *gin.Context, ids []string) map[string]bool {
+	if !middleware.Personalized(c) {
+		return map[string]bool{}
+	}
+	likedMap, _ := h.repo.AreLiked(ids, getUserIP(c))
+	return likedMap
+}
+
+// maxBatchIDs - максимальное количество ID в одном запросе
+const maxBatchIDs = 100
+
+// parseIDs разбирает параметр ids (ID цитат через запятую), сохраняя порядок и удаляя повторы
+func parseIDs(c *gin.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 || len(ids) > maxBatchIDs {
+		return nil, fmt.Errorf("ids must contain from 1 to %d quote IDs", maxBatchIDs)
+	}
+	return ids, nil
+}
+
+// parseQuoteFilter разбирает параметры фильтрации списка цитат из query
+func parseQuoteFilter(c *gin.Context) (models.QuoteFilter, error) {
+	filter := models.QuoteFilter{
+		Search:            c.Query("search"),
+		Author:            c.Query("author"),
+		AttributionStatus: c.Query("attribution_status"),
+	}
+
+	if filter.AttributionStatus != "" && !models.IsValidAttributionStatus(filter.AttributionStatus) {
+		return filter, errors.New("invalid attribution_status")
+	}
+
+	if raw := c.Query("has_source"); raw != "" {
+		hasSource, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, errors.New("invalid has_source")
+		}
+		filter.HasSource = &hasSource
+	}
+
+	return filter, nil
+}
+
+// applyUpdate применяет к цитате поля, переданные в запросе на обновление
+func applyUpdate(quote *models.Quote, req models.UpdateQuoteRequest) {
+	if req.Text != "" {
+		quote.Text = req.Text
+	}
+	if req.Author != "" {
+		quote.Author = req.Author
+	}
+	if req.Source != nil {
+		quote.Source = req.Source
+		if req.Source.IsEmpty() {
+			quote.Source = nil
+		}
+	}
+	if req.AttributionStatus != "" {
+		quote.AttributionStatus = req.AttributionStatus
+	}
+	if req.AttributionNote != nil {
+		quote.AttributionNote = *req.AttributionNote
+	}
+	if req.Status != "" {
+		quote.Status = req.Status
+	}
+	if req.PublishAt != nil {
+		quote.PublishAt = req.PublishAt
+	}
+}
+
+// getQuote возвращает цитату для чтения или редактирования
+// Администратор получает также черновики, запланированные и скрытые по жалобам цитаты
+func (h *QuoteHandler) getQuote(c *gin.Context, id string) (*models.Quote, error) {
+	if middleware.IsAdmin(c) {
+		return h.repo.GetEditable(id)
+	}
+	return h.repo.GetByID(id)
+}
+
 // GetRandom возвращает случайную цитату
 // @Summary Получить случайную цитату
 // @Description Возвращает одну случайную цитату из базы данных
 // @Tags quotes
 // @Accept json
-// @Produce json
+// @Produce json,xml,plain,application/msgpack
+// @Param fields query string false "Поля цитаты через запятую (id возвращается всегда), например text,author"
+// @Param include query string false "Связанные данные через запятую: stats, author"
 // @Success 200 {object} models.QuoteResponse
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/quotes/random [get]
@@ -55,7 +216,7 @@ func (h *QuoteHandler) GetRandom(c *gin.Context) {
 	userIP := getUserIP(c)
 	isLiked, _ := h.repo.IsLiked(quote.ID, userIP)
 
-	c.JSON(http.StatusOK, quote.ToResponse(isLiked))
+	h.respond(c, http.StatusOK, quote.ToResponse(isLiked))
 }
 
 // GetAll возвращает все цитаты с пагинацией
@@ -63,10 +224,16 @@ func (h *QuoteHandler) GetRandom(c *gin.Context) {
 // @Description Возвращает список цитат с пагинацией и возможностью поиска
 // @Tags quotes
 // @Accept json
-// @Produce json
+// @Produce json,xml,plain,application/msgpack
 // @Param page query int false "Номер страницы" default(1)
 // @Param page_size query int false "Размер страницы" default(10)
-// @Param search query string false "Поисковый запрос"
+// @Param search query string false "Поисковый запрос (учитывает псевдонимы и транслитерацию автора)"
+// @Param author query string false "Фильтр по автору (учитывает псевдонимы и транслитерацию)"
+// @Param attribution_status query string false "Фильтр по статусу атрибуции (verified, disputed, misattributed, unknown)"
+// @Param has_source query bool false "Фильтр по наличию источника"
+// @Param personalized query bool false "false - не заполнять is_liked, ответ кэшируется CDN" default(true)
+// @Param fields query string false "Поля цитаты через запятую (id возвращается всегда), например text,author"
+// @Param include query string false "Связанные данные через запятую: stats, author"
 // @Success 200 {object} models.PaginatedQuotesResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -74,7 +241,11 @@ func (h *QuoteHandler) GetRandom(c *gin.Context) {
 func (h *QuoteHandler) GetAll(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-	search := c.Query("search")
+	filter, err := parseQuoteFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	if page < 1 {
 		page = 1
@@ -83,21 +254,20 @@ func (h *QuoteHandler) GetAll(c *gin.Context) {
 		pageSize = 10
 	}
 
-	quotes, total, err := h.repo.GetAll(page, pageSize, search)
+	quotes, total, err := h.repo.GetAll(page, pageSize, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Оптимизация: batch проверка лайков вместо N+1 запросов
-	userIP := getUserIP(c)
 	quoteIDs := make([]string, len(quotes))
 	for i, quote := range quotes {
 		quoteIDs[i] = quote.ID
 	}
-	
-	likedMap, _ := h.repo.AreLiked(quoteIDs, userIP)
-	
+
+	likedMap := h.likedMap(c, quoteIDs)
+
 	responses := make([]models.QuoteResponse, len(quotes))
 	for i, quote := range quotes {
 		isLiked := likedMap[quote.ID]
@@ -106,7 +276,7 @@ func (h *QuoteHandler) GetAll(c *gin.Context) {
 
 	totalPages := repository.CalculateTotalPages(total, pageSize)
 
-	c.JSON(http.StatusOK, models.PaginatedQuotesResponse{
+	h.respond(c, http.StatusOK, models.PaginatedQuotesResponse{
 		Quotes:     responses,
 		Total:      total,
 		Page:       page,
@@ -117,57 +287,116 @@ func (h *QuoteHandler) GetAll(c *gin.Context) {
 
 // GetByID возвращает цитату по ID
 // @Summary Получить цитату по ID
-// @Description Возвращает цитату с указанным ID
+// @Description Возвращает цитату с указанным ID. Администратор также получает черновики, запланированные и скрытые цитаты
 // @Tags quotes
 // @Accept json
-// @Produce json
+// @Produce json,xml,plain,application/msgpack
 // @Param id path string true "ID цитаты"
+// @Param personalized query bool false "false - не заполнять is_liked, ответ кэшируется CDN" default(true)
+// @Param fields query string false "Поля цитаты через запятую (id возвращается всегда), например text,author"
+// @Param include query string false "Связанные данные через запятую: stats, author"
 // @Success 200 {object} models.QuoteResponse
+// @Header 200 {string} ETag "Версия цитаты для заголовка If-Match"
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/quotes/{id} [get]
 func (h *QuoteHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	quote, err := h.repo.GetByID(id)
+	quote, err := h.getQuote(c, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Проверяем, лайкнул ли текущий пользователь эту цитату
-	userIP := getUserIP(c)
-	isLiked, _ := h.repo.IsLiked(quote.ID, userIP)
-
-	c.JSON(http.StatusOK, quote.ToResponse(isLiked))
+	c.Header("ETag", quoteETag(quote))
+	c.Header("Last-Modified", quote.UpdatedAt.UTC().Format(http.TimeFormat))
+	h.respond(c, http.StatusOK, quote.ToResponse(h.isLiked(c, quote.ID)))
 }
 
 // Create создает новую цитату
 // @Summary Создать новую цитату
-// @Description Создает новую цитату в базе данных
+// @Description Цитата администратора публикуется сразу; цитата посетителя попадает в очередь модерации
+// @Description (ответ 202, статус pending) и не отображается в списках до одобрения.
+// @Description Ответ 409 содержит опубликованные дубликаты; неопубликованные (на модерации, скрытые, черновики) видит только администратор
 // @Tags quotes
 // @Accept json
 // @Produce json
 // @Param quote body models.CreateQuoteRequest true "Данные цитаты"
+// @Param allow_duplicate query bool false "Создать цитату, даже если найдены дубликаты (только для администратора)"
 // @Success 201 {object} models.QuoteResponse
-// @Failure 400 {object} map[string]string
+// @Success 202 {object} models.QuoteResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} models.DuplicateConflictResponse
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/quotes [post]
 func (h *QuoteHandler) Create(c *gin.Context) {
 	var req models.CreateQuoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindingError(c, err)
+		return
+	}
+
+	if (req.Status != "" || req.PublishAt != nil) && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "status and publish_at require admin token"})
+		return
+	}
+
+	allowDuplicate, _ := strconv.ParseBool(c.Query("allow_duplicate"))
+	if allowDuplicate && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "allow_duplicate requires admin token"})
 		return
 	}
 
 	quote := models.NewQuote(req)
-	if err := h.repo.Create(quote); err != nil {
+	if err := h.normalize(quote); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	// Проверяем дубликаты: совпадение текста без учета регистра и пунктуации или похожий текст
+	if !allowDuplicate {
+		duplicates, err := h.repo.FindDuplicates(quote.Text)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(duplicates) > 0 {
+			// Среди дубликатов могут быть неопубликованные цитаты (на модерации, скрытые, черновики),
+			// их тексты видит только администратор; посетителю возвращаются только опубликованные
+			if !middleware.IsAdmin(c) {
+				duplicates = visibleDuplicates(duplicates)
+			}
+			response := models.DuplicateConflictResponse{
+				Error:      "quote already exists",
+				Duplicates: duplicateResponses(duplicates),
+			}
+			c.JSON(http.StatusConflict, response)
+			return
+		}
+	}
+
+	// Предложения посетителей публикуются только после модерации
+	if !middleware.IsAdmin(c) {
+		quote.Status = models.StatusPending
+	}
+
+	if err := h.repo.Create(quote, getEditor(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	status := http.StatusCreated
+	if quote.Status == models.StatusPending {
+		status = http.StatusAccepted
+	}
+
 	// Новая цитата не может быть лайкнута
-	c.JSON(http.StatusCreated, quote.ToResponse(false))
+	c.Header("ETag", quoteETag(quote))
+	h.respond(c, status, quote.ToResponse(false))
 }
 
 // Update обновляет существующую цитату
@@ -176,11 +405,15 @@ func (h *QuoteHandler) Create(c *gin.Context) {
 // @Tags quotes
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "ID цитаты"
+// @Param If-Match header string false "ETag цитаты, полученный при чтении; при несовпадении возвращается 412"
 // @Param quote body models.UpdateQuoteRequest true "Обновленные данные цитаты"
 // @Success 200 {object} models.QuoteResponse
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/quotes/{id} [put]
 func (h *QuoteHandler) Update(c *gin.Context) {
@@ -188,59 +421,102 @@ func (h *QuoteHandler) Update(c *gin.Context) {
 
 	var req models.UpdateQuoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindingError(c, err)
+		return
+	}
+
+	quote, ok := h.loadForUpdate(c, id)
+	if !ok {
+		return
+	}
+
+	applyUpdate(quote, req)
+	if err := h.normalize(quote); err != nil {
+		respondValidationError(c, err)
 		return
 	}
 
+	h.saveUpdate(c, id, quote)
+}
+
+// loadForUpdate загружает цитату для изменения и проверяет заголовок If-Match
+// Если цитата не найдена или изменена после того, как клиент ее прочитал, отправляет ответ с ошибкой и возвращает false
+func (h *QuoteHandler) loadForUpdate(c *gin.Context, id string) (*models.Quote, bool) {
+	expectedVersion, ok := parseIfMatch(c)
+	if !ok {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "invalid If-Match"})
+		return nil, false
+	}
+
 	// Получаем существующую цитату
-	quote, err := h.repo.GetByID(id)
+	quote, err := h.getQuote(c, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		return
+		return nil, false
 	}
 
-	// Обновляем поля
-	if req.Text != "" {
-		quote.Text = req.Text
-	}
-	if req.Author != "" {
-		quote.Author = req.Author
+	// Цитата изменена после того, как клиент ее прочитал
+	if expectedVersion != 0 && expectedVersion != quote.Version {
+		c.Header("ETag", quoteETag(quote))
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": repository.ErrQuoteModified.Error()})
+		return nil, false
 	}
 
-	if err := h.repo.Update(id, quote); err != nil {
+	return quote, true
+}
+
+// saveUpdate сохраняет измененную цитату (с записью ревизии) и возвращает ее в ответе
+func (h *QuoteHandler) saveUpdate(c *gin.Context, id string, quote *models.Quote) {
+	// Запись условная: если цитату изменили между чтением и записью, возвращается ErrQuoteModified
+	if err := h.repo.Update(id, quote, getEditor(c)); err != nil {
+		if errors.Is(err, repository.ErrQuoteModified) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Получаем обновленную цитату
-	updatedQuote, err := h.repo.GetByID(id)
+	updatedQuote, err := h.getQuote(c, id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Проверяем, лайкнул ли текущий пользователь эту цитату
-	userIP := getUserIP(c)
-	isLiked, _ := h.repo.IsLiked(updatedQuote.ID, userIP)
-
-	c.JSON(http.StatusOK, updatedQuote.ToResponse(isLiked))
+	c.Header("ETag", quoteETag(updatedQuote))
+	h.respond(c, http.StatusOK, updatedQuote.ToResponse(h.isLiked(c, updatedQuote.ID)))
 }
 
-// Delete удаляет цитату
+// Delete перемещает цитату в корзину
 // @Summary Удалить цитату
-// @Description Удаляет цитату по ID
+// @Description Перемещает цитату в корзину по ID. Цитата скрывается из всех списков, лайки сохраняются
 // @Tags quotes
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "ID цитаты"
+// @Param If-Match header string false "ETag цитаты, полученный при чтении; при несовпадении возвращается 412"
 // @Success 204
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/quotes/{id} [delete]
 func (h *QuoteHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.repo.Delete(id); err != nil {
+	expectedVersion, ok := parseIfMatch(c)
+	if !ok {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "invalid If-Match"})
+		return
+	}
+
+	if err := h.repo.Delete(id, expectedVersion); err != nil {
+		if errors.Is(err, repository.ErrQuoteModified) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -285,7 +561,7 @@ func (h *QuoteHandler) Like(c *gin.Context) {
 	}
 
 	// После лайка пользователь точно лайкнул эту цитату
-	c.JSON(http.StatusOK, quote.ToResponse(true))
+	h.respond(c, http.StatusOK, quote.ToResponse(true))
 }
 
 // GetTopWeekly возвращает топ цитату за неделю
@@ -293,8 +569,12 @@ func (h *QuoteHandler) Like(c *gin.Context) {
 // @Description Возвращает цитату с наибольшим количеством лайков за последние 7 дней
 // @Tags quotes
 // @Accept json
-// @Produce json
+// @Produce json,xml,plain,application/msgpack
+// @Param personalized query bool false "false - не заполнять is_liked, ответ кэшируется CDN" default(true)
+// @Param fields query string false "Поля цитаты через запятую (id возвращается всегда), например text,author"
+// @Param include query string false "Связанные данные через запятую: stats, author"
 // @Success 200 {object} models.QuoteResponse
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/quotes/top/weekly [get]
@@ -305,11 +585,7 @@ func (h *QuoteHandler) GetTopWeekly(c *gin.Context) {
 		return
 	}
 
-	// Проверяем, лайкнул ли текущий пользователь эту цитату
-	userIP := getUserIP(c)
-	isLiked, _ := h.repo.IsLiked(quote.ID, userIP)
-
-	c.JSON(http.StatusOK, quote.ToResponse(isLiked))
+	h.respond(c, http.StatusOK, quote.ToResponse(h.isLiked(c, quote.ID)))
 }
 
 // GetTopAllTime возвращает топ цитату за всё время
@@ -317,8 +593,12 @@ func (h *QuoteHandler) GetTopWeekly(c *gin.Context) {
 // @Description Возвращает цитату с наибольшим количеством лайков за всё время
 // @Tags quotes
 // @Accept json
-// @Produce json
+// @Produce json,xml,plain,application/msgpack
+// @Param personalized query bool false "false - не заполнять is_liked, ответ кэшируется CDN" default(true)
+// @Param fields query string false "Поля цитаты через запятую (id возвращается всегда), например text,author"
+// @Param include query string false "Связанные данные через запятую: stats, author"
 // @Success 200 {object} models.QuoteResponse
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/quotes/top/alltime [get]
@@ -329,11 +609,100 @@ func (h *QuoteHandler) GetTopAllTime(c *gin.Context) {
 		return
 	}
 
-	// Проверяем, лайкнул ли текущий пользователь эту цитату
-	userIP := getUserIP(c)
-	isLiked, _ := h.repo.IsLiked(quote.ID, userIP)
+	h.respond(c, http.StatusOK, quote.ToResponse(h.isLiked(c, quote.ID)))
+}
+
+// GetLikes возвращает цитаты, лайкнутые текущим пользователем
+// @Summary Получить лайки текущего пользователя
+// @Description Возвращает ID цитат из списка ids, которые лайкнул текущий пользователь. Используется вместе с ?personalized=false
+// @Tags quotes
+// @Accept json
+// @Produce json
+// @Param ids query string true "ID цитат через запятую (не больше 100)"
+// @Success 200 {object} models.LikedQuotesResponse
+// @Failure 400 {object} map[string]string
+// @Router /api/quotes/likes [get]
+func (h *QuoteHandler) GetLikes(c *gin.Context) {
+	ids, err := parseIDs(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	likedMap, err := h.repo.AreLiked(ids, getUserIP(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	liked := make([]string, 0, len(likedMap))
+	for _, id := range ids {
+		if likedMap[id] {
+			liked = append(liked, id)
+		}
+	}
+
+	// Ответ зависит от клиента и меняется при каждом лайке
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, models.LikedQuotesResponse{Liked: liked})
+}
+
+// GetBatch возвращает несколько цитат по ID одним запросом
+// @Summary Получить цитаты по списку ID
+// @Description Возвращает цитаты в порядке ids (повторы ID не дублируются). Не найденные, удаленные и скрытые цитаты
+// @Description перечисляются в missing. Используется, например, для списка избранного, хранящегося на клиенте
+// @Tags quotes
+// @Accept json
+// @Produce json,xml,plain,application/msgpack
+// @Param ids query string true "ID цитат через запятую (не больше 100)"
+// @Param personalized query bool false "false - не заполнять is_liked, ответ кэшируется CDN" default(true)
+// @Param fields query string false "Поля цитаты через запятую (id возвращается всегда), например text,author"
+// @Param include query string false "Связанные данные через запятую: stats, author"
+// @Success 200 {object} models.BatchQuotesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/quotes/batch [get]
+func (h *QuoteHandler) GetBatch(c *gin.Context) {
+	ids, err := parseIDs(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quotes, err := h.repo.GetByIDs(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	byID := make(map[string]*models.Quote, len(quotes))
+	for i := range quotes {
+		byID[quotes[i].ID] = &quotes[i]
+	}
+
+	// Оптимизация: лайки всех найденных цитат проверяются одним запросом
+	foundIDs := make([]string, 0, len(quotes))
+	for _, id := range ids {
+		if _, ok := byID[id]; ok {
+			foundIDs = append(foundIDs, id)
+		}
+	}
+	likedMap := h.likedMap(c, foundIDs)
+
+	response := models.BatchQuotesResponse{
+		Quotes:  make([]models.QuoteResponse, 0, len(foundIDs)),
+		Missing: []string{},
+	}
+	for _, id := range ids {
+		quote, ok := byID[id]
+		if !ok {
+			response.Missing = append(response.Missing, id)
+			continue
+		}
+		response.Quotes = append(response.Quotes, quote.ToResponse(likedMap[id]))
+	}
 
-	c.JSON(http.StatusOK, quote.ToResponse(isLiked))
+	h.respond(c, http.StatusOK, response)
 }
 
 // ResetLikes сбрасывает все лайки
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler обрабатывает HTTP запросы для жалоб на цитаты
+type ReportHandler struct {
+	repo repository.ReportRepository
+	// hideThreshold - количество открытых жалоб, после которого цитата скрывается автоматически (0 - не скрывать)
+	hideThreshold int
+}
+
+// NewReportHandler создает новый экземпляр обработчика жалоб
+func NewReportHandler(repo repository.ReportRepository, hideThreshold int) *ReportHandler {
+	return &ReportHandler{repo: repo, hideThreshold: hideThreshold}
+}
+
+// Create принимает жалобу на цитату
+// @Summary Пожаловаться на цитату
+// @Description Сохраняет жалобу с причиной и комментарием. Один пользователь может оставить одну открытую жалобу на цитату.
+// @Description Цитата скрывается автоматически, если набрала REPORT_HIDE_THRESHOLD открытых жалоб
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path string true "ID цитаты"
+// @Param report body models.CreateReportRequest true "Причина (offensive, incorrect, misattributed, duplicate, spam, other) и комментарий"
+// @Success 201 {object} models.ReportCreatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/quotes/{id}/reports [post]
+func (h *ReportHandler) Create(c *gin.Context) {
+	var req models.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+
+	// Жалобы учитываются по IP соединения (X-Forwarded-For только от доверенных прокси),
+	// чтобы нельзя было обойти ограничение одной жалобы, подставив заголовок
+	report := models.NewQuoteReport(c.Param("id"), req, c.ClientIP(), c.GetHeader("User-Agent"))
+	hidden, err := h.repo.Create(report, h.hideThreshold)
+	if err != nil {
+		switch err.Error() {
+		case "quote not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "quote already reported":
+			c.JSON(http.StatusConflict, gin.H{"error": "Вы уже пожаловались на эту цитату"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ReportCreatedResponse{ID: report.ID, Hidden: hidden})
+}
+
+// GetFlagged возвращает цитаты с открытыми жалобами
+// @Summary Получить цитаты с жалобами
+// @Description Возвращает цитаты с открытыми жалобами (включая скрытые), начиная с цитат с наибольшим количеством жалоб
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/reports [get]
+func (h *ReportHandler) GetFlagged(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	flagged, total, err := h.repo.GetFlagged(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]models.FlaggedQuoteResponse, len(flagged))
+	for i := range flagged {
+		responses[i] = flagged[i].ToResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"quotes":      responses,
+		"total":       total,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_pages": repository.CalculateTotalPages(total, pageSize),
+	})
+}
+
+// GetReports возвращает все жалобы на цитату
+// @Summary Получить жалобы на цитату
+// @Description Возвращает открытые и закрытые жалобы на цитату, начиная с последних
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID цитаты"
+// @Success 200 {array} models.QuoteReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/reports/{id} [get]
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	reports, err := h.repo.GetReports(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// Resolve закрывает открытые жалобы на цитату
+// @Summary Рассмотреть жалобы на цитату
+// @Description Закрывает все открытые жалобы на цитату с решением: dismiss (цитата снова отображается),
+// @Description hide (цитата скрывается) или delete (цитата перемещается в корзину)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID цитаты"
+// @Param request body models.ResolveReportsRequest true "Решение и комментарий"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/reports/{id}/resolve [post]
+func (h *ReportHandler) Resolve(c *gin.Context) {
+	var req models.ResolveReportsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resolved, err := h.repo.Resolve(c.Param("id"), req.Action, strings.TrimSpace(req.Note))
+	if err != nil {
+		if err.Error() == "no open reports" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resolved": resolved, "action": req.Action})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"quotes-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRevisions возвращает историю изменений цитаты
+// @Summary Получить историю изменений цитаты
+// @Description Возвращает ревизии цитаты (от новых к старым) со списком изменившихся полей и пословным diff текста
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path string true "ID цитаты"
+// @Success 200 {array} models.QuoteRevisionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/quotes/{id}/revisions [get]
+func (h *QuoteHandler) GetRevisions(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := h.getQuote(c, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	revisions, err := h.repo.GetRevisions(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]models.QuoteRevisionResponse, len(revisions))
+	for i := range revisions {
+		responses[i] = revisions[i].ToResponse()
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// RestoreRevision восстанавливает цитату до состояния указанной ревизии
+// @Summary Восстановить ревизию цитаты
+// @Description Возвращает цитате значения полей, которые она получила в указанной ревизии. Восстановление записывается как новая ревизия
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID цитаты"
+// @Param revisionId path string true "ID ревизии"
+// @Param If-Match header string false "ETag цитаты, полученный при чтении; при несовпадении возвращается 412"
+// @Success 200 {object} models.QuoteResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/quotes/{id}/revisions/{revisionId}/restore [post]
+func (h *QuoteHandler) RestoreRevision(c *gin.Context) {
+	id := c.Param("id")
+
+	revision, err := h.repo.GetRevision(id, c.Param("revisionId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, ok := h.loadForUpdate(c, id)
+	if !ok {
+		return
+	}
+
+	// Ревизия могла быть записана до введения текущих ограничений длины и правил типографики
+	quote.ApplySnapshot(revision.New)
+	if err := h.normalize(quote); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	h.saveUpdate(c, id, quote)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"quotes-backend/internal/middleware"
+	"quotes-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respond отправляет ответ с цитатами с учетом параметров fields и include (см. middleware.Shape)
+// body - models.QuoteResponse, models.PaginatedQuotesResponse, models.BatchQuotesResponse или []models.ScheduledDay
+func (h *QuoteHandler) respond(c *gin.Context, status int, body interface{}) {
+	shape := middleware.GetShape(c)
+
+	// Связанные данные записываются по указателям: срезы ответов разделяют память с body
+	var quotes []*models.QuoteResponse
+	switch v := body.(type) {
+	case models.QuoteResponse:
+		quotes = []*models.QuoteResponse{&v}
+		body = &v
+	case models.PaginatedQuotesResponse:
+		quotes = quotePointers(v.Quotes)
+	case models.BatchQuotesResponse:
+		quotes = quotePointers(v.Quotes)
+	case []models.ScheduledDay:
+		for i := range v {
+			quotes = append(quotes, quotePointers(v[i].Quotes)...)
+		}
+	}
+
+	if err := h.embed(quotes, shape.Include); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if shape.Fields == nil {
+		c.JSON(status, body)
+		return
+	}
+
+	filtered, err := filterFields(body, shape)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(status, filtered)
+}
+
+// embed заполняет связанные данные, запрошенные параметром include
+// Данные всех цитат ответа загружаются одним запросом на каждый вид
+func (h *QuoteHandler) embed(quotes []*models.QuoteResponse, include map[string]bool) error {
+	if len(quotes) == 0 || len(include) == 0 {
+		return nil
+	}
+
+	if include[models.IncludeStats] {
+		ids := make([]string, len(quotes))
+		for i, quote := range quotes {
+			ids[i] = quote.ID
+		}
+		stats, err := h.repo.GetStats(ids)
+		if err != nil {
+			return err
+		}
+		for _, quote := range quotes {
+			s := stats[quote.ID]
+			quote.Stats = &s
+		}
+	}
+
+	if include[models.IncludeAuthor] {
+		seen := make(map[string]bool)
+		var authors []string
+		for _, quote := range quotes {
+			if !seen[quote.Author] {
+				seen[quote.Author] = true
+				authors = append(authors, quote.Author)
+			}
+		}
+		details, err := h.repo.GetAuthorDetails(authors)
+		if err != nil {
+			return err
+		}
+		for _, quote := range quotes {
+			d := details[quote.Author]
+			d.Name = quote.Author
+			if d.Aliases == nil {
+				d.Aliases = []string{}
+			}
+			quote.AuthorDetails = &d
+		}
+	}
+
+	return nil
+}
+
+// filterFields оставляет в цитатах ответа только поля из параметра fields
+// Поле id и запрошенные параметром include связанные данные сохраняются всегда
+func filterFields(body interface{}, shape middleware.ResponseShape) (interface{}, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	keep := map[string]bool{"id": true}
+	for field := range shape.Fields {
+		keep[field] = true
+	}
+	if shape.Include[models.IncludeStats] {
+		keep["stats"] = true
+	}
+	if shape.Include[models.IncludeAuthor] {
+		keep["author_details"] = true
+	}
+
+	switch body.(type) {
+	case *models.QuoteResponse:
+		filterQuote(doc, keep)
+	case models.PaginatedQuotesResponse, models.BatchQuotesResponse:
+		filterQuoteList(doc, keep)
+	case []models.ScheduledDay:
+		days, _ := doc.([]interface{})
+		for _, day := range days {
+			filterQuoteList(day, keep)
+		}
+	}
+
+	return doc, nil
+}
+
+// filterQuoteList применяет filterQuote к каждой цитате в поле quotes объекта
+func filterQuoteList(container interface{}, keep map[string]bool) {
+	object, _ := container.(map[string]interface{})
+	quotes, _ := object["quotes"].([]interface{})
+	for _, quote := range quotes {
+		filterQuote(quote, keep)
+	}
+}
+
+// filterQuote удаляет из объекта цитаты поля, не входящие в keep
+func filterQuote(quote interface{}, keep map[string]bool) {
+	object, _ := quote.(map[string]interface{})
+	for field := range object {
+		if !keep[field] {
+			delete(object, field)
+		}
+	}
+}
+
+// quotePointers возвращает указатели на элементы среза ответов
+func quotePointers(quotes []models.QuoteResponse) []*models.QuoteResponse {
+	pointers := make([]*models.QuoteResponse, len(quotes))
+	for i := range quotes {
+		pointers[i] = &quotes[i]
+	}
+	return pointers
+}
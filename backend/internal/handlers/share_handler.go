@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"quotes-backend/internal/repository"
+	"quotes-backend/internal/typography"
+
+	"github.com/gin-gonic/gin"
+)
+
+// shareDescriptionLength - максимальная длина описания страницы в символах
+// Социальные сети обрезают более длинные описания в превью
+const shareDescriptionLength = 300
+
+// shareSiteName - название сайта в превью
+const shareSiteName = "Цитаты"
+
+// sharePage - страница цитаты для превью в социальных сетях и мессенджерах
+// Роботы читают метатеги и не выполняют скрипт; браузер сразу переходит в SPA
+var sharePage = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<meta name="description" content="{{.Description}}">
+<link rel="canonical" href="{{.CanonicalURL}}">
+<meta property="og:type" content="article">
+<meta property="og:site_name" content="{{.SiteName}}">
+<meta property="og:locale" content="{{.Locale}}">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:url" content="{{.CanonicalURL}}">
+{{- if .ImageURL}}
+<meta property="og:image" content="{{.ImageURL}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image" content="{{.ImageURL}}">
+{{- else}}
+<meta name="twitter:card" content="summary">
+{{- end}}
+<meta name="twitter:title" content="{{.Title}}">
+<meta name="twitter:description" content="{{.Description}}">
+<script>window.location.replace({{.AppURL}})</script>
+</head>
+<body>
+<blockquote>{{.Text}}</blockquote>
+<p>— {{.Author}}</p>
+<p><a href="{{.AppURL}}">Открыть цитату</a></p>
+</body>
+</html>
+`))
+
+// shareNotFoundPage - страница для несуществующих и скрытых цитат
+var shareNotFoundPage = template.Must(template.New("share_not_found").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>Цитата не найдена</title>
+<meta name="robots" content="noindex">
+</head>
+<body>
+<p>Цитата не найдена. <a href="{{.}}">Перейти на сайт</a></p>
+</body>
+</html>
+`))
+
+// sharePageData - данные шаблона sharePage
+type sharePageData struct {
+	Lang         string
+	Locale       string
+	SiteName     string
+	Title        string
+	Description  string
+	Text         string
+	Author       string
+	CanonicalURL string
+	AppURL       string
+	ImageURL     string
+}
+
+// ShareHandler отдает страницы цитат с метатегами OpenGraph и Twitter Card и карту сайта
+type ShareHandler struct {
+	repo repository.QuoteRepository
+	// baseURL - публичный адрес сайта (без завершающего /); страница цитаты в SPA - baseURL/quote/:id
+	baseURL string
+	// imageURL - изображение для превью (пустое - превью без изображения)
+	imageURL string
+}
+
+// NewShareHandler создает новый экземпляр обработчика страниц цитат
+func NewShareHandler(repo repository.QuoteRepository, baseURL, imageURL string) *ShareHandler {
+	return &ShareHandler{repo: repo, baseURL: strings.TrimSuffix(baseURL, "/"), imageURL: imageURL}
+}
+
+// GetSharePage возвращает страницу цитаты для превью ссылки
+// @Summary Страница цитаты для превью
+// @Description HTML страница с метатегами OpenGraph и Twitter Card (заголовок, текст цитаты, изображение) и каноническим адресом.
+// @Description Браузер сразу перенаправляется на страницу цитаты в SPA (/quote/:id); роботы социальных сетей читают метатеги
+// @Tags share
+// @Produce html
+// @Param id path string true "ID цитаты"
+// @Success 200 {string} string "HTML страница"
+// @Failure 404 {string} string "Цитата не найдена"
+// @Router /q/{id} [get]
+func (h *ShareHandler) GetSharePage(c *gin.Context) {
+	quote, err := h.repo.GetByID(c.Param("id"))
+	if err != nil {
+		var buf bytes.Buffer
+		if err := shareNotFoundPage.Execute(&buf, h.baseURL+"/"); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusNotFound, "text/html; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	lang, locale := typography.LanguageRussian, "ru_RU"
+	if typography.DetectLanguage(quote.Text) == typography.LanguageEnglish {
+		lang, locale = typography.LanguageEnglish, "en_US"
+	}
+
+	var buf bytes.Buffer
+	err = sharePage.Execute(&buf, sharePageData{
+		Lang:         lang,
+		Locale:       locale,
+		SiteName:     shareSiteName,
+		Title:        quote.Author,
+		Description:  truncate(quote.Text, shareDescriptionLength),
+		Text:         quote.Text,
+		Author:       quote.Author,
+		CanonicalURL: shareURL(h.baseURL, quote.ID),
+		AppURL:       h.baseURL + "/quote/" + quote.ID,
+		ImageURL:     h.imageURL,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Last-Modified", quote.UpdatedAt.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// sitemapPageSize - количество цитат на странице карты сайта
+// Протокол допускает до 50 000 адресов в файле; меньшие страницы читаются короткими запросами
+const sitemapPageSize = 10000
+
+// sitemapNamespace - пространство имен протокола Sitemaps
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// sitemapIndex - индекс карт сайта
+type sitemapIndex struct {
+	XMLName  xml.Name          `xml:"sitemapindex"`
+	XMLNS    string            `xml:"xmlns,attr"`
+	Sitemaps []sitemapLocation `xml:"sitemap"`
+}
+
+// sitemapLocation - элемент sitemap индекса карт сайта
+type sitemapLocation struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// sitemapURLSet - страница карты сайта
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sitemapURL - элемент url карты сайта
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// GetSitemap возвращает индекс карт сайта
+// @Summary Индекс карт сайта
+// @Description sitemap.xml в формате индекса: ссылки на страницы карты сайта (/sitemap-quotes.xml) по 10 000 опубликованных цитат
+// @Description и дата последнего изменения цитат каждой страницы
+// @Tags share
+// @Produce xml
+// @Success 200 {string} string "sitemap.xml"
+// @Failure 500 {object} map[string]string
+// @Router /sitemap.xml [get]
+func (h *ShareHandler) GetSitemap(c *gin.Context) {
+	pages, err := h.repo.GetSitemapPages(sitemapPageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	index := sitemapIndex{XMLNS: sitemapNamespace, Sitemaps: make([]sitemapLocation, len(pages))}
+	for i, page := range pages {
+		loc := h.baseURL + "/sitemap-quotes.xml"
+		if page.FromID != "" {
+			loc += "?from=" + url.QueryEscape(page.FromID)
+		}
+		index.Sitemaps[i] = sitemapLocation{Loc: loc}
+		if !page.LastModified.IsZero() {
+			index.Sitemaps[i].LastMod = page.LastModified.UTC().Format("2006-01-02")
+		}
+	}
+
+	writeSitemap(c, index)
+}
+
+// GetSitemapPage возвращает страницу карты сайта
+// @Summary Страница карты сайта
+// @Description Страницы /q/:id опубликованных цитат с ID не меньше from (до 10 000 цитат) и дата их последнего изменения.
+// @Description Первая страница (без from) также содержит главную страницу сайта. Ссылки на страницы перечислены в /sitemap.xml
+// @Tags share
+// @Produce xml
+// @Param from query string false "ID первой цитаты страницы"
+// @Success 200 {string} string "Страница карты сайта"
+// @Failure 500 {object} map[string]string
+// @Router /sitemap-quotes.xml [get]
+func (h *ShareHandler) GetSitemapPage(c *gin.Context) {
+	from := c.Query("from")
+	entries, err := h.repo.GetSitemapEntries(from, sitemapPageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	set := sitemapURLSet{XMLNS: sitemapNamespace, URLs: make([]sitemapURL, 0, len(entries)+1)}
+	if from == "" {
+		set.URLs = append(set.URLs, sitemapURL{Loc: h.baseURL + "/"})
+	}
+	for _, entry := range entries {
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     shareURL(h.baseURL, entry.ID),
+			LastMod: entry.UpdatedAt.UTC().Format("2006-01-02"),
+		})
+	}
+
+	writeSitemap(c, set)
+}
+
+// writeSitemap кодирует документ карты сайта
+// Страница уже прочитана из базы целиком, поэтому медленный клиент не держит соединение с базой
+func writeSitemap(c *gin.Context, doc interface{}) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	buf.WriteString("\n")
+
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", buf.Bytes())
+}
+
+// shareURL возвращает публичный адрес страницы цитаты для превью
+func shareURL(baseURL, id string) string {
+	return baseURL + "/q/" + id
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrash возвращает цитаты из корзины
+// @Summary Получить корзину
+// @Description Возвращает удаленные цитаты с пагинацией, начиная с последних удаленных
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10)
+// @Success 200 {object} models.PaginatedQuotesResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/trash [get]
+func (h *QuoteHandler) GetTrash(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	quotes, total, err := h.repo.GetTrash(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]models.QuoteResponse, len(quotes))
+	for i, quote := range quotes {
+		responses[i] = quote.ToResponse(false)
+	}
+
+	h.respond(c, http.StatusOK, models.PaginatedQuotesResponse{
+		Quotes:     responses,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: repository.CalculateTotalPages(total, pageSize),
+	})
+}
+
+// RestoreFromTrash восстанавливает цитату из корзины
+// @Summary Восстановить цитату из корзины
+// @Description Возвращает цитату из корзины вместе со всеми ее лайками
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID цитаты"
+// @Success 200 {object} models.QuoteResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/trash/{id}/restore [post]
+func (h *QuoteHandler) RestoreFromTrash(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.repo.Restore(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.getQuote(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respond(c, http.StatusOK, quote.ToResponse(false))
+}
+
+// PurgeFromTrash окончательно удаляет цитату из корзины
+// @Summary Удалить цитату навсегда
+// @Description Окончательно удаляет цитату из корзины вместе с лайками и историей изменений
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID цитаты"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/admin/trash/{id} [delete]
+func (h *QuoteHandler) PurgeFromTrash(c *gin.Context) {
+	if err := h.repo.Purge(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/typography"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreviewTypography показывает результат нормализации типографики без сохранения
+// @Summary Предпросмотр нормализации типографики
+// @Description Возвращает текст с расставленными кавычками («ёлочки» для русского, “лапки” для английского), тире,
+// @Description неразрывными пробелами перед тире и многоточиями. Работает независимо от TYPOGRAPHY_ENABLED
+// @Tags typography
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TypographyPreviewRequest true "Текст и параметры нормализации"
+// @Success 200 {object} models.TypographyPreviewResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Router /api/admin/typography/preview [post]
+func (h *QuoteHandler) PreviewTypography(c *gin.Context) {
+	var req models.TypographyPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	opts := typography.Options{Language: req.Language}
+	if h.typography != nil {
+		opts.RestoreYo = h.typography.RestoreYo
+	}
+	if req.RestoreYo != nil {
+		opts.RestoreYo = *req.RestoreYo
+	}
+	if opts.Language == "" {
+		opts.Language = typography.DetectLanguage(req.Text)
+	}
+
+	normalized := typography.Normalize(req.Text, opts)
+
+	c.JSON(http.StatusOK, models.TypographyPreviewResponse{
+		Text:       req.Text,
+		Normalized: normalized,
+		Language:   opts.Language,
+		Changed:    normalized != req.Text,
+	})
+}
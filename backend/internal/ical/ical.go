@@ -0,0 +1,121 @@
+// Package ical формирует календари в формате iCalendar (RFC 5545)
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType - тип содержимого календаря
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineLength - максимальная длина строки в октетах без учета CRLF (RFC 5545, 3.1)
+const maxLineLength = 75
+
+// Calendar - календарь событий
+type Calendar struct {
+	ProdID  string        // Идентификатор программы, создавшей календарь
+	Name    string        // Название календаря в клиенте (X-WR-CALNAME)
+	Refresh time.Duration // Рекомендуемый интервал обновления подписки (0 - не указывать)
+	Events  []Event
+}
+
+// Event - событие на целый день
+// Клиенты обновляют существующее событие по UID, поэтому UID должен быть постоянным для даты
+type Event struct {
+	UID         string
+	Date        time.Time // Дата события; время не учитывается
+	Summary     string
+	Description string
+	URL         string
+	Modified    time.Time // Время последнего изменения (DTSTAMP, LAST-MODIFIED)
+}
+
+// Encode кодирует календарь в iCalendar
+func Encode(cal *Calendar) []byte {
+	var w writer
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + escape(cal.ProdID))
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		w.line("X-WR-CALNAME:" + escape(cal.Name))
+	}
+	if cal.Refresh > 0 {
+		duration := formatDuration(cal.Refresh)
+		w.line("REFRESH-INTERVAL;VALUE=DURATION:" + duration)
+		w.line("X-PUBLISHED-TTL:" + duration)
+	}
+
+	for _, event := range cal.Events {
+		modified := event.Modified.UTC().Format("20060102T150405Z")
+		w.line("BEGIN:VEVENT")
+		w.line("UID:" + escape(event.UID))
+		w.line("DTSTAMP:" + modified)
+		w.line("LAST-MODIFIED:" + modified)
+		w.line("DTSTART;VALUE=DATE:" + event.Date.Format("20060102"))
+		w.line("DTEND;VALUE=DATE:" + event.Date.AddDate(0, 0, 1).Format("20060102"))
+		w.line("SUMMARY:" + escape(event.Summary))
+		if event.Description != "" {
+			w.line("DESCRIPTION:" + escape(event.Description))
+		}
+		if event.URL != "" {
+			w.line("URL:" + event.URL)
+		}
+		// Цитата не занимает время в календаре
+		w.line("TRANSP:TRANSPARENT")
+		w.line("END:VEVENT")
+	}
+
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// writer записывает строки содержимого с CRLF и переносом длинных строк
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line записывает строку, разбивая ее на части не длиннее maxLineLength октетов
+// Продолжение начинается с пробела; многобайтовые символы UTF-8 не разрываются
+func (w *writer) line(s string) {
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// Пробел в начале продолжения занимает один октет
+		limit = maxLineLength - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
+
+// escape экранирует значение типа TEXT (RFC 5545, 3.3.11)
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// formatDuration кодирует интервал в формате DURATION с точностью до минут (PT12H, PT90M)
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Minutes())
+	if minutes%60 == 0 {
+		return "PT" + strconv.Itoa(minutes/60) + "H"
+	}
+	return "PT" + strconv.Itoa(minutes) + "M"
+}
@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"quotes-backend/internal/models"
+)
+
+// FormatFortune - формат файлов fortune(6): записи разделены строками "%",
+// автор указывается последней строкой вида "-- Автор"
+const FormatFortune = "fortune"
+
+// fortuneAttribution распознает строку атрибуции: "-- Автор", "— Автор" или "― Автор"
+var fortuneAttribution = regexp.MustCompile(`^\s*(?:--|—|―)\s*(.+?)\s*$`)
+
+// fortuneSource распознает название источника в кавычках после автора: `Автор, "Название"`
+var fortuneSource = regexp.MustCompile(`^(.+?),\s*["«“](.+)["»”]$`)
+
+// fortuneReader читает записи файла fortune
+type fortuneReader struct {
+	scanner *bufio.Scanner
+	line    int
+	done    bool
+}
+
+func newFortuneReader(r io.Reader) *fortuneReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return &fortuneReader{scanner: scanner}
+}
+
+func (r *fortuneReader) Next() (*Record, error) {
+	for !r.done {
+		var lines []string
+		start := r.line + 1
+
+		// Читаем строки до разделителя "%" или конца файла
+		for {
+			if !r.scanner.Scan() {
+				if err := r.scanner.Err(); err != nil {
+					return nil, fmt.Errorf("failed to read fortune file: %w", err)
+				}
+				r.done = true
+				break
+			}
+			r.line++
+			line := strings.TrimRight(r.scanner.Text(), " \t\r")
+			if line == "%" {
+				break
+			}
+			lines = append(lines, line)
+		}
+
+		if record := parseFortune(lines); record != nil {
+			record.Line = start
+			return record, nil
+		}
+	}
+
+	return nil, io.EOF
+}
+
+// parseFortune превращает строки одной записи fortune в Record
+// Возвращает nil для пустой записи
+func parseFortune(lines []string) *Record {
+	// Убираем пустые строки по краям
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	record := &Record{Author: models.UnknownAuthor}
+
+	if len(lines) > 1 {
+		if match := fortuneAttribution.FindStringSubmatch(lines[len(lines)-1]); match != nil {
+			record.Author = match[1]
+			if source := fortuneSource.FindStringSubmatch(match[1]); source != nil {
+				record.Author = source[1]
+				record.Source = &models.QuoteSource{Title: source[2]}
+			}
+			lines = lines[:len(lines)-1]
+		}
+	}
+
+	record.Text = strings.TrimSpace(strings.Join(lines, "\n"))
+	return record
+}
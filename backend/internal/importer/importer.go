@@ -0,0 +1,272 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/repository"
+	"quotes-backend/internal/typography"
+	"quotes-backend/internal/validation"
+)
+
+// Режимы импорта
+const (
+	// ModeAtomic - все записи сохраняются в одной транзакции; любая ошибка отменяет импорт целиком
+	ModeAtomic = "atomic"
+	// ModePartial - корректные записи сохраняются, ошибочные попадают в отчет
+	ModePartial = "partial"
+)
+
+// DefaultBatchSize - количество записей, обрабатываемых за одну пачку
+const DefaultBatchSize = 500
+
+// maxReportedErrors ограничивает количество ошибок в отчете, чтобы он оставался компактным
+const maxReportedErrors = 100
+
+// maxPreviewRecords ограничивает количество записей предпросмотра в режиме dry run
+const maxPreviewRecords = 100
+
+// ErrImportRolledBack возвращается, если атомарный импорт отменен из-за ошибок в записях
+var ErrImportRolledBack = errors.New("import rolled back due to invalid rows")
+
+// Store определяет операции хранилища, необходимые для импорта
+type Store interface {
+	InTransaction(fn func(w repository.QuoteWriter) error) error
+	FindExisting(quotes []*models.Quote, accepted []string) (map[string]bool, error)
+}
+
+// Options содержит параметры импорта
+type Options struct {
+	Format    string
+	Mode      string
+	DryRun    bool // Только проверить файл, ничего не сохраняя
+	BatchSize int
+	Editor    string            // Автор изменений для истории ревизий
+	Limits    validation.Limits // Ограничения длины полей (см. validation.Limits)
+	// Typography - параметры нормализации типографики (nil - текст не изменяется)
+	Typography *typography.Options
+}
+
+// Summary содержит итоги импорта
+type Summary struct {
+	Format  string     `json:"format"`
+	Mode    string     `json:"mode"`
+	DryRun  bool       `json:"dry_run"`
+	Total   int        `json:"total"`   // Количество прочитанных записей
+	Created int        `json:"created"` // Созданные цитаты (в режиме dry run - которые были бы созданы)
+	Skipped int        `json:"skipped"` // Дубликаты (в том числе похожие) уже существующих цитат или записей файла
+	Failed  int        `json:"failed"`  // Записи с ошибками
+	Errors  []RowError `json:"errors"`
+	Preview []Record   `json:"preview,omitempty"` // Первые записи, которые были бы созданы (только в режиме dry run)
+}
+
+// addError учитывает ошибку записи в отчете
+func (s *Summary) addError(line int, err error) {
+	s.Failed++
+	if len(s.Errors) < maxReportedErrors {
+		s.Errors = append(s.Errors, RowError{Line: line, Error: err.Error()})
+	}
+}
+
+// pendingQuote - цитата, ожидающая записи в пачке
+type pendingQuote struct {
+	record *Record
+	quote  *models.Quote
+}
+
+// importRun хранит состояние одного запуска импорта
+type importRun struct {
+	store   Store
+	opts    Options
+	summary *Summary
+	seen    map[string]bool
+	batch   []pendingQuote
+	writer  repository.QuoteWriter // Общая транзакция в атомарном режиме
+	// accepted - тексты записей, принятых в режиме dry run: они не сохраняются,
+	// поэтому следующие пачки сравниваются с ними явно
+	accepted []string
+}
+
+// Run читает записи из reader и создает цитаты в хранилище
+// Файл читается потоково, записи проверяются и сохраняются пачками по opts.BatchSize
+// Возвращает итоги импорта; ошибка означает, что импорт прерван или отменен
+func Run(reader Reader, store Store, opts Options) (*Summary, error) {
+	if opts.Mode == "" {
+		opts.Mode = ModeAtomic
+	}
+	if opts.Mode != ModeAtomic && opts.Mode != ModePartial {
+		return nil, fmt.Errorf("unsupported import mode %q", opts.Mode)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	run := &importRun{
+		store: store,
+		opts:  opts,
+		summary: &Summary{
+			Format: opts.Format,
+			Mode:   opts.Mode,
+			DryRun: opts.DryRun,
+			Errors: []RowError{},
+		},
+		seen: make(map[string]bool),
+	}
+
+	if opts.DryRun || opts.Mode == ModePartial {
+		return run.summary, run.process(reader)
+	}
+
+	err := store.InTransaction(func(w repository.QuoteWriter) error {
+		run.writer = w
+		if err := run.process(reader); err != nil {
+			return err
+		}
+		if run.summary.Failed > 0 {
+			return ErrImportRolledBack
+		}
+		return nil
+	})
+	if err != nil {
+		run.summary.Created = 0
+	}
+
+	return run.summary, err
+}
+
+// process читает все записи и сохраняет их пачками
+func (r *importRun) process(reader Reader) error {
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var rowErr *rowError
+			if errors.As(err, &rowErr) {
+				r.summary.Total++
+				r.summary.addError(rowErr.line, rowErr.err)
+				continue
+			}
+			return err
+		}
+
+		r.summary.Total++
+		if err := record.Validate(); err != nil {
+			r.summary.addError(record.Line, err)
+			continue
+		}
+
+		quote := record.ToQuote()
+		if r.opts.Typography != nil {
+			typography.Quote(quote, *r.opts.Typography)
+		}
+		if err := r.opts.Limits.Quote(quote); err != nil {
+			r.summary.addError(record.Line, err)
+			continue
+		}
+
+		key := repository.QuoteKey(quote.Text)
+		if r.seen[key] {
+			r.summary.Skipped++
+			continue
+		}
+		r.seen[key] = true
+
+		r.batch = append(r.batch, pendingQuote{record: record, quote: quote})
+		if len(r.batch) >= r.opts.BatchSize {
+			if err := r.flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.flush()
+}
+
+// flush сохраняет накопленную пачку, пропуская уже существующие цитаты
+// и цитаты, похожие на более ранние записи файла
+func (r *importRun) flush() error {
+	if len(r.batch) == 0 {
+		return nil
+	}
+	batch := r.batch
+	r.batch = nil
+
+	quotes := make([]*models.Quote, len(batch))
+	for i, item := range batch {
+		quotes[i] = item.quote
+	}
+	// В атомарном режиме поиск выполняется в общей транзакции, чтобы видеть цитаты предыдущих пачек
+	var existing map[string]bool
+	var err error
+	if r.writer != nil {
+		existing, err = r.writer.FindExisting(quotes, r.accepted)
+	} else {
+		existing, err = r.store.FindExisting(quotes, r.accepted)
+	}
+	if err != nil {
+		return err
+	}
+
+	fresh := batch[:0]
+	for _, item := range batch {
+		if existing[repository.QuoteKey(item.quote.Text)] {
+			r.summary.Skipped++
+			continue
+		}
+		fresh = append(fresh, item)
+	}
+
+	switch {
+	case r.opts.DryRun:
+		r.summary.Created += len(fresh)
+		for _, item := range fresh {
+			r.accepted = append(r.accepted, item.quote.Text)
+			if len(r.summary.Preview) < maxPreviewRecords {
+				r.summary.Preview = append(r.summary.Preview, *item.record)
+			}
+		}
+		return nil
+	case r.writer != nil:
+		// Атомарный режим: ошибка базы данных прерывает весь импорт
+		for _, item := range fresh {
+			if err := r.writer.Insert(item.quote, r.opts.Editor); err != nil {
+				r.summary.addError(item.record.Line, err)
+				return err
+			}
+			r.summary.Created++
+		}
+		return nil
+	}
+
+	// Частичный режим: пачка сохраняется в своей транзакции,
+	// при ошибке записи пачки повторяются по одной, чтобы найти ошибочные
+	err = r.store.InTransaction(func(w repository.QuoteWriter) error {
+		for _, item := range fresh {
+			if err := w.Insert(item.quote, r.opts.Editor); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		r.summary.Created += len(fresh)
+		return nil
+	}
+
+	for _, item := range fresh {
+		err := r.store.InTransaction(func(w repository.QuoteWriter) error {
+			return w.Insert(item.quote, r.opts.Editor)
+		})
+		if err != nil {
+			r.summary.addError(item.record.Line, err)
+			continue
+		}
+		r.summary.Created++
+	}
+
+	return nil
+}
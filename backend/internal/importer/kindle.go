@@ -0,0 +1,289 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"quotes-backend/internal/models"
+)
+
+// FormatKindle - файл "My Clippings.txt" с выделениями Kindle
+const FormatKindle = "kindle"
+
+// kindleSeparator разделяет записи в файле Kindle
+const kindleSeparator = "=========="
+
+// Типы записей Kindle
+const (
+	kindleHighlight = "highlight"
+	kindleNote      = "note"
+	kindleBookmark  = "bookmark"
+)
+
+var (
+	// kindleTitle выделяет название книги и автора в скобках в конце строки
+	kindleTitle = regexp.MustCompile(`^(.*?)\s*\(([^()]*)\)\s*$`)
+	// kindleLocation распознает номер места: "Location 170-172", "место 170-172"
+	kindleLocation = regexp.MustCompile(`(?i)(?:location|loc\.|мест[оеа]|позици[яи])\s+(\d+)(?:\s*[-–]\s*(\d+))?`)
+	// kindlePage распознает номер страницы: "page 12", "странице 12"
+	kindlePage = regexp.MustCompile(`(?i)(?:page|странице|стр\.)\s+([\divxlc]+)`)
+	// kindleNameSuffix распознает суффиксы имени, которые не являются именем после запятой
+	kindleNameSuffix = regexp.MustCompile(`(?i)^(jr|sr|[ivx]+)\.?$`)
+	// kindleAdded выделяет дату добавления
+	kindleAdded = regexp.MustCompile(`(?i)(?:added on|добавлено:?)\s+(.+)$`)
+)
+
+// kindleDateLayouts содержит известные форматы даты добавления
+var kindleDateLayouts = []string{
+	"Monday, January 2, 2006 3:04:05 PM",
+	"Monday, 2 January 2006 15:04:05",
+	"Monday, January 2, 2006, 3:04 PM",
+}
+
+// kindleClipping представляет одну запись файла Kindle
+type kindleClipping struct {
+	line          int
+	title         string
+	author        string
+	kind          string
+	page          string
+	locationStart int
+	locationEnd   int
+	added         time.Time
+	text          string
+}
+
+// overlaps проверяет, что два выделения из одной книги повторяют друг друга:
+// диапазон мест одного содержит диапазон другого или текст одного содержит текст другого
+// Соседние выделения, диапазоны которых только соприкасаются (100-102 и 102-105), считаются разными
+func (c *kindleClipping) overlaps(other *kindleClipping) bool {
+	if c.title != other.title || c.author != other.author {
+		return false
+	}
+	if c.locationStart > 0 && other.locationStart > 0 && (c.containsRange(other) || other.containsRange(c)) {
+		return true
+	}
+	return strings.Contains(c.text, other.text) || strings.Contains(other.text, c.text)
+}
+
+// containsRange проверяет, что диапазон мест выделения содержит диапазон другого выделения
+func (c *kindleClipping) containsRange(other *kindleClipping) bool {
+	return c.locationStart <= other.locationStart && other.locationEnd <= c.locationEnd
+}
+
+// better проверяет, что выделение предпочтительнее другого повторяющегося
+// Предпочтение отдается более длинному тексту, при равной длине - более позднему
+func (c *kindleClipping) better(other *kindleClipping) bool {
+	if len(c.text) != len(other.text) {
+		return len(c.text) > len(other.text)
+	}
+	return !c.added.Before(other.added)
+}
+
+// toRecord преобразует выделение в запись импорта
+func (c *kindleClipping) toRecord() *Record {
+	record := &Record{
+		Line:   c.line,
+		Text:   c.text,
+		Author: c.author,
+	}
+	if record.Author == "" {
+		record.Author = models.UnknownAuthor
+	}
+
+	source := &models.QuoteSource{Title: c.title, Page: c.page}
+	if source.Page == "" && c.locationStart > 0 {
+		source.Page = "loc. " + strconv.Itoa(c.locationStart)
+		if c.locationEnd > c.locationStart {
+			source.Page += "-" + strconv.Itoa(c.locationEnd)
+		}
+	}
+	if !source.IsEmpty() {
+		record.Source = source
+	}
+
+	return record
+}
+
+// kindleReader читает файл Kindle целиком, пропускает заметки и закладки
+// и удаляет повторяющиеся выделения, оставляя самые полные
+type kindleReader struct {
+	records []*Record
+	errors  []*rowError
+}
+
+func newKindleReader(r io.Reader) (*kindleReader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	reader := &kindleReader{}
+	var clippings []*kindleClipping
+	var lines []string
+	line, start := 0, 1
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\ufeff"), " \t\r")
+		if strings.TrimSpace(text) != kindleSeparator {
+			lines = append(lines, text)
+			continue
+		}
+
+		clipping, err := parseKindleClipping(lines)
+		if err != nil {
+			reader.errors = append(reader.errors, &rowError{line: start, err: err})
+		} else if clipping != nil && clipping.kind == kindleHighlight {
+			clipping.line = start
+			clippings = append(clippings, clipping)
+		}
+		lines = nil
+		start = line + 1
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Kindle clippings: %w", err)
+	}
+
+	for _, clipping := range dedupeKindleClippings(clippings) {
+		reader.records = append(reader.records, clipping.toRecord())
+	}
+
+	return reader, nil
+}
+
+func (r *kindleReader) Next() (*Record, error) {
+	if len(r.errors) > 0 {
+		err := r.errors[0]
+		r.errors = r.errors[1:]
+		return nil, err
+	}
+	if len(r.records) == 0 {
+		return nil, io.EOF
+	}
+
+	record := r.records[0]
+	r.records = r.records[1:]
+	return record, nil
+}
+
+// parseKindleClipping разбирает строки одной записи
+// Возвращает nil для пустой записи
+func parseKindleClipping(lines []string) (*kindleClipping, error) {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("clipping has no metadata line")
+	}
+
+	clipping := &kindleClipping{title: strings.TrimSpace(lines[0])}
+	if match := kindleTitle.FindStringSubmatch(clipping.title); match != nil {
+		clipping.title = strings.TrimSpace(match[1])
+		clipping.author = normalizeKindleAuthor(match[2])
+	}
+
+	meta := strings.TrimSpace(lines[1])
+	lowerMeta := strings.ToLower(meta)
+	switch {
+	case strings.Contains(lowerMeta, "bookmark") || strings.Contains(lowerMeta, "закладка"):
+		clipping.kind = kindleBookmark
+	case strings.Contains(lowerMeta, "note") || strings.Contains(lowerMeta, "заметка"):
+		clipping.kind = kindleNote
+	case strings.Contains(lowerMeta, "highlight") || strings.Contains(lowerMeta, "выделен"):
+		clipping.kind = kindleHighlight
+	default:
+		return nil, fmt.Errorf("unknown clipping type %q", meta)
+	}
+
+	if match := kindlePage.FindStringSubmatch(meta); match != nil {
+		clipping.page = match[1]
+	}
+	if match := kindleLocation.FindStringSubmatch(meta); match != nil {
+		clipping.locationStart, _ = strconv.Atoi(match[1])
+		clipping.locationEnd = clipping.locationStart
+		if match[2] != "" {
+			end, _ := strconv.Atoi(match[2])
+			clipping.locationEnd = expandLocationEnd(clipping.locationStart, match[2], end)
+		}
+	}
+	if match := kindleAdded.FindStringSubmatch(meta); match != nil {
+		for _, layout := range kindleDateLayouts {
+			if added, err := time.Parse(layout, strings.TrimSpace(match[1])); err == nil {
+				clipping.added = added
+				break
+			}
+		}
+	}
+
+	clipping.text = strings.TrimSpace(strings.Join(lines[2:], "\n"))
+	if clipping.kind == kindleHighlight && clipping.text == "" {
+		return nil, nil
+	}
+
+	return clipping, nil
+}
+
+// normalizeKindleAuthor приводит имя автора из формата "Фамилия, Имя" к "Имя Фамилия"
+func normalizeKindleAuthor(author string) string {
+	author = strings.TrimSpace(author)
+	parts := strings.Split(author, ",")
+	if len(parts) != 2 {
+		return author
+	}
+
+	last, first := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if last == "" || first == "" || kindleNameSuffix.MatchString(first) {
+		return author
+	}
+
+	return first + " " + last
+}
+
+// expandLocationEnd восстанавливает сокращенный конец диапазона мест
+// Старые Kindle записывают "1234-56" вместо "1234-1256"
+func expandLocationEnd(start int, raw string, end int) int {
+	if end >= start {
+		return end
+	}
+	startDigits := strconv.Itoa(start)
+	if len(raw) < len(startDigits) {
+		expanded, err := strconv.Atoi(startDigits[:len(startDigits)-len(raw)] + raw)
+		if err == nil && expanded >= start {
+			return expanded
+		}
+	}
+	return start
+}
+
+// dedupeKindleClippings удаляет повторяющиеся выделения, сохраняя порядок файла
+// Kindle создает новую запись при каждом расширении выделения, старая при этом остается в файле
+func dedupeKindleClippings(clippings []*kindleClipping) []*kindleClipping {
+	var result []*kindleClipping
+
+	for _, clipping := range clippings {
+		replaced := false
+		for i, kept := range result {
+			if !kept.overlaps(clipping) {
+				continue
+			}
+			if clipping.better(kept) {
+				clipping.line = kept.line
+				result[i] = clipping
+			}
+			replaced = true
+			break
+		}
+		if !replaced {
+			result = append(result, clipping)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,242 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"quotes-backend/internal/models"
+)
+
+// Поддерживаемые форматы импорта
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// maxLineSize - максимальный размер одной строки NDJSON
+const maxLineSize = 1024 * 1024
+
+// Reader последовательно читает записи из файла импорта
+// Next возвращает io.EOF, когда записи закончились
+// Ошибка отдельной записи возвращается как *rowError, после нее чтение можно продолжать
+type Reader interface {
+	Next() (*Record, error)
+}
+
+// NewReader создает потоковый читатель для указанного формата
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSON:
+		return newJSONReader(r)
+	case FormatNDJSON:
+		return newNDJSONReader(r), nil
+	case FormatFortune:
+		return newFortuneReader(r), nil
+	case FormatKindle:
+		return newKindleReader(r)
+	case FormatWikiquote:
+		return NewWikiquoteReader(r, WikiquoteOptions{}), nil
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+// DetectFormat определяет формат по Content-Type или расширению файла
+// Возвращает пустую строку, если формат определить не удалось
+func DetectFormat(contentType, filename string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "text/csv":
+			return FormatCSV
+		case "application/json":
+			return FormatJSON
+		case "application/x-ndjson", "application/ndjson", "application/jsonl":
+			return FormatNDJSON
+		}
+	}
+
+	// Kindle всегда сохраняет выделения в файл "My Clippings.txt"
+	if strings.Contains(strings.ToLower(filepath.Base(filename)), "clippings") {
+		return FormatKindle
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	case ".fortune":
+		return FormatFortune
+	}
+
+	return ""
+}
+
+// csvReader читает CSV с заголовком
+// Обязательные колонки: text, author. Дополнительные: source_title, source_year,
+// source_page, source_url, source_context, attribution_status, attribution_note
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, required := range []string{"text", "author"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header must contain %q column", required)
+		}
+	}
+
+	return &csvReader{r: cr, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (*Record, error) {
+	row, err := r.r.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			return nil, &rowError{line: parseErr.Line, err: parseErr.Err}
+		}
+		return nil, err
+	}
+	line, _ := r.r.FieldPos(0)
+
+	get := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	record := &Record{
+		Line:              line,
+		Text:              get("text"),
+		Author:            get("author"),
+		AttributionStatus: get("attribution_status"),
+		AttributionNote:   get("attribution_note"),
+	}
+
+	source := &models.QuoteSource{
+		Title:   get("source_title"),
+		Page:    get("source_page"),
+		URL:     get("source_url"),
+		Context: get("source_context"),
+	}
+	if year := get("source_year"); year != "" {
+		value, err := strconv.Atoi(year)
+		if err != nil {
+			return nil, &rowError{line: line, err: fmt.Errorf("invalid source_year %q", year)}
+		}
+		source.Year = &value
+	}
+	if !source.IsEmpty() {
+		record.Source = source
+	}
+
+	return record, nil
+}
+
+// jsonReader потоково читает JSON массив объектов, не загружая его в память целиком
+type jsonReader struct {
+	dec   *json.Decoder
+	index int
+	done  bool
+}
+
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	dec := json.NewDecoder(r)
+
+	token, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("JSON import expects an array of quotes")
+	}
+
+	return &jsonReader{dec: dec}, nil
+}
+
+func (r *jsonReader) Next() (*Record, error) {
+	if r.done || !r.dec.More() {
+		r.done = true
+		return nil, io.EOF
+	}
+
+	r.index++
+	var record Record
+	if err := r.dec.Decode(&record); err != nil {
+		// Синтаксическая ошибка в массиве делает дальнейшее чтение невозможным
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return nil, &rowError{line: r.index, err: err}
+		}
+		return nil, fmt.Errorf("item %d: %w", r.index, err)
+	}
+	record.Line = r.index
+
+	return &record, nil
+}
+
+// ndjsonReader читает по одному JSON объекту на строку
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return &ndjsonReader{scanner: scanner}
+}
+
+func (r *ndjsonReader) Next() (*Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, &rowError{line: r.line, err: err}
+		}
+		record.Line = r.line
+
+		return &record, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read NDJSON: %w", err)
+	}
+
+	return nil, io.EOF
+}
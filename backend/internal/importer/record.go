@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"quotes-backend/internal/models"
+)
+
+// Record представляет одну запись импортируемого файла
+type Record struct {
+	Line              int                 `json:"line,omitempty"` // Номер строки (или записи) в исходном файле
+	Text              string              `json:"text"`
+	Author            string              `json:"author"`
+	Source            *models.QuoteSource `json:"source,omitempty"`
+	AttributionStatus string              `json:"attribution_status,omitempty"`
+	AttributionNote   string              `json:"attribution_note,omitempty"`
+}
+
+// Validate проверяет, что запись может быть сохранена как цитата
+func (r *Record) Validate() error {
+	r.Text = strings.TrimSpace(r.Text)
+	r.Author = strings.TrimSpace(r.Author)
+
+	if r.Text == "" {
+		return fmt.Errorf("text is required")
+	}
+	if r.Author == "" {
+		return fmt.Errorf("author is required")
+	}
+	if r.AttributionStatus != "" && !models.IsValidAttributionStatus(r.AttributionStatus) {
+		return fmt.Errorf("invalid attribution_status %q", r.AttributionStatus)
+	}
+
+	return nil
+}
+
+// ToQuote создает новую цитату из записи
+func (r *Record) ToQuote() *models.Quote {
+	return models.NewQuote(models.CreateQuoteRequest{
+		Text:              r.Text,
+		Author:            r.Author,
+		Source:            r.Source,
+		AttributionStatus: r.AttributionStatus,
+		AttributionNote:   r.AttributionNote,
+	})
+}
+
+// RowError описывает ошибку в отдельной записи файла
+// Такие ошибки не прерывают чтение остальных записей
+type RowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// rowError оборачивает ошибку разбора отдельной записи
+type rowError struct {
+	line int
+	err  error
+}
+
+func (e *rowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.line, e.err)
+}
+
+func (e *rowError) Unwrap() error {
+	return e.err
+}
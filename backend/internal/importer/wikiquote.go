@@ -0,0 +1,315 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"quotes-backend/internal/models"
+)
+
+// FormatWikiquote - XML дамп MediaWiki с Викицитатника (pages-articles.xml)
+const FormatWikiquote = "wikiquote"
+
+// WikiquoteOptions содержит параметры разбора дампа Викицитатника
+type WikiquoteOptions struct {
+	// AllPages отключает проверку категорий и импортирует цитаты со всех статей,
+	// а не только со страниц персоналий
+	AllPages bool
+}
+
+// wikiPage представляет страницу дампа MediaWiki
+type wikiPage struct {
+	Title    string    `xml:"title"`
+	NS       int       `xml:"ns"`
+	Redirect *struct{} `xml:"redirect"`
+	Text     string    `xml:"revision>text"`
+}
+
+// wikiquoteSection описывает, как обрабатывать цитаты раздела статьи
+type wikiquoteSection struct {
+	skip        bool   // Раздел не содержит цитат автора
+	attribution string // Статус атрибуции для цитат раздела
+}
+
+var (
+	// wikiHeading распознает заголовок раздела: "== Цитаты =="
+	wikiHeading = regexp.MustCompile(`^(={2,6})\s*(.+?)\s*={2,6}\s*$`)
+	// wikiCategory распознает ссылку на категорию
+	wikiCategory = regexp.MustCompile(`(?i)\[\[\s*(?:category|категория)\s*:\s*([^\]|]+)`)
+	// wikiPersonCategory распознает категории страниц о людях
+	wikiPersonCategory = regexp.MustCompile(`(?i)births|deaths|people|персоналии|родившиеся|умершие|писатели|поэты|философы|политики|учёные|ученые`)
+	// wikiQuoteTemplate распознает шаблон цитаты Русского Викицитатника {{Q|Цитата=...}}
+	wikiQuoteTemplate = regexp.MustCompile(`(?is)^\{\{\s*(?:q|цитата)\s*\|(.*)\}\}$`)
+	// wikiDisambiguation распознает уточнение в названии страницы: "Имя (писатель)"
+	wikiDisambiguation = regexp.MustCompile(`\s*\([^()]*\)$`)
+)
+
+// wikiSkippedSections содержит начала названий разделов, в которых нет цитат автора
+var wikiSkippedSections = []string{
+	"quotes about", "about ", "external links", "see also", "references", "sources", "notes",
+	"further reading", "bibliography",
+	"цитаты о ", "о нём", "о нем", "о ней", "ссылки", "см. также", "примечания", "источники",
+	"литература", "высказывания о",
+}
+
+// wikiDisputedSections и wikiMisattributedSections задают статус атрибуции для цитат раздела
+var (
+	wikiDisputedSections      = []string{"disputed", "спорные", "сомнительные", "неподтверждённые", "неподтвержденные"}
+	wikiMisattributedSections = []string{"misattributed", "приписываемые", "ошибочно приписываемые", "ошибочно приписанные"}
+)
+
+// wikiquoteReader потоково читает дамп, обрабатывая страницы по одной
+type wikiquoteReader struct {
+	dec     *xml.Decoder
+	opts    WikiquoteOptions
+	baseURL string
+	pending []*Record
+	pages   int
+}
+
+// NewWikiquoteReader создает читатель дампа Викицитатника с указанными параметрами
+func NewWikiquoteReader(r io.Reader, opts WikiquoteOptions) Reader {
+	dec := xml.NewDecoder(r)
+	// Некоторые выгрузки содержат HTML сущности без экранирования
+	dec.Entity = xml.HTMLEntity
+	return &wikiquoteReader{dec: dec, opts: opts}
+}
+
+func (r *wikiquoteReader) Next() (*Record, error) {
+	for len(r.pending) == 0 {
+		page, err := r.nextPage()
+		if err != nil {
+			return nil, err
+		}
+		r.pending = r.parsePage(page)
+	}
+
+	record := r.pending[0]
+	r.pending = r.pending[1:]
+	return record, nil
+}
+
+// nextPage возвращает следующую страницу основного пространства имен
+func (r *wikiquoteReader) nextPage() (*wikiPage, error) {
+	for {
+		token, err := r.dec.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read wikiquote dump: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "base":
+			// <siteinfo><base> содержит адрес заглавной страницы, из него получаем адрес статей
+			var base string
+			if err := r.dec.DecodeElement(&base, &start); err == nil {
+				if i := strings.LastIndex(base, "/"); i >= 0 {
+					r.baseURL = base[:i+1]
+				}
+			}
+		case "page":
+			var page wikiPage
+			if err := r.dec.DecodeElement(&page, &start); err != nil {
+				return nil, fmt.Errorf("failed to read wikiquote page: %w", err)
+			}
+			r.pages++
+			if page.NS != 0 || page.Redirect != nil {
+				continue
+			}
+			return &page, nil
+		}
+	}
+}
+
+// parsePage извлекает цитаты со страницы автора
+func (r *wikiquoteReader) parsePage(page *wikiPage) []*Record {
+	if !r.opts.AllPages && !isPersonPage(page.Text) {
+		return nil
+	}
+
+	author := strings.TrimSpace(wikiDisambiguation.ReplaceAllString(page.Title, ""))
+	source := models.QuoteSource{Title: page.Title}
+	if r.baseURL != "" {
+		source.URL = r.baseURL + url.PathEscape(strings.ReplaceAll(page.Title, " ", "_"))
+	}
+
+	var records []*Record
+	section := wikiquoteSection{}
+	var last *Record
+
+	for _, line := range strings.Split(page.Text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+
+		if match := wikiHeading.FindStringSubmatch(line); match != nil {
+			// Разделы третьего уровня и ниже наследуют правила родительского раздела
+			if len(match[1]) == 2 {
+				section = classifySection(cleanWikitext(match[2]))
+			}
+			last = nil
+			continue
+		}
+		if section.skip {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "**"):
+			// Вложенный пункт содержит источник или комментарий к предыдущей цитате
+			if last != nil && last.AttributionNote == "" {
+				last.AttributionNote = cleanWikitext(strings.TrimLeft(line, "*:"))
+			}
+		case strings.HasPrefix(line, "*"):
+			text := strings.TrimSpace(strings.TrimPrefix(line, "*"))
+			if match := wikiQuoteTemplate.FindStringSubmatch(text); match != nil {
+				text = quoteTemplateText(match[1])
+			}
+			text = cleanWikitext(text)
+			if text == "" {
+				last = nil
+				continue
+			}
+
+			pageSource := source
+			last = &Record{
+				Line:              r.pages, // Для дампа номером записи служит порядковый номер страницы
+				Source:            &pageSource,
+				Text:              text,
+				Author:            author,
+				AttributionStatus: section.attribution,
+			}
+			records = append(records, last)
+		default:
+			last = nil
+		}
+	}
+
+	return records
+}
+
+// isPersonPage проверяет по категориям, что страница посвящена человеку
+func isPersonPage(text string) bool {
+	for _, match := range wikiCategory.FindAllStringSubmatch(text, -1) {
+		if wikiPersonCategory.MatchString(match[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifySection определяет правила обработки раздела по его названию
+func classifySection(title string) wikiquoteSection {
+	title = strings.ToLower(title)
+
+	for _, prefix := range wikiMisattributedSections {
+		if strings.HasPrefix(title, prefix) {
+			return wikiquoteSection{attribution: models.AttributionMisattributed}
+		}
+	}
+	for _, prefix := range wikiDisputedSections {
+		if strings.HasPrefix(title, prefix) {
+			return wikiquoteSection{attribution: models.AttributionDisputed}
+		}
+	}
+	for _, prefix := range wikiSkippedSections {
+		if strings.HasPrefix(title, prefix) {
+			return wikiquoteSection{skip: true}
+		}
+	}
+
+	return wikiquoteSection{}
+}
+
+// quoteTemplateText извлекает текст цитаты из параметров шаблона {{Q|...}}
+// Используется именованный параметр "Цитата" (или "quote"), иначе первый позиционный
+func quoteTemplateText(params string) string {
+	var positional string
+	for _, param := range splitTemplateParams(params) {
+		name, value, named := strings.Cut(param, "=")
+		if !named {
+			if positional == "" {
+				positional = param
+			}
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "цитата", "quote", "текст", "text":
+			return value
+		}
+	}
+	return positional
+}
+
+// splitTemplateParams разбивает параметры шаблона по "|" без учета вложенных ссылок и шаблонов
+func splitTemplateParams(params string) []string {
+	var result []string
+	depth, start := 0, 0
+	for i := 0; i < len(params); i++ {
+		switch params[i] {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		case '|':
+			if depth == 0 {
+				result = append(result, params[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, params[start:])
+}
+
+var (
+	wikiComment     = regexp.MustCompile(`(?s)<!--.*?-->`)
+	wikiRef         = regexp.MustCompile(`(?is)<ref[^>/]*/>|<ref[^>]*>.*?</ref>`)
+	wikiTemplate    = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+	wikiFileLink    = regexp.MustCompile(`(?i)\[\[\s*(?:file|image|файл|изображение)\s*:[^\]]*\]\]`)
+	wikiPipedLink   = regexp.MustCompile(`\[\[[^\]|]*\|([^\]]*)\]\]`)
+	wikiLink        = regexp.MustCompile(`\[\[([^\]]*)\]\]`)
+	wikiExternal    = regexp.MustCompile(`\[(?:https?:)?//[^\s\]]+\s*([^\]]*)\]`)
+	wikiBreak       = regexp.MustCompile(`(?i)<br\s*/?>`)
+	wikiTag         = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	wikiEmphasis    = regexp.MustCompile(`'{2,}`)
+	wikiSpaces      = regexp.MustCompile(`[ \t]+`)
+	wikiLineSpacing = regexp.MustCompile(`\s*\n\s*`)
+)
+
+// cleanWikitext удаляет разметку MediaWiki: ссылки, шаблоны, сноски, выделение и HTML теги
+func cleanWikitext(text string) string {
+	text = wikiComment.ReplaceAllString(text, "")
+	text = wikiRef.ReplaceAllString(text, "")
+
+	// Шаблоны могут быть вложенными, удаляем их изнутри наружу
+	for {
+		cleaned := wikiTemplate.ReplaceAllString(text, "")
+		if cleaned == text {
+			break
+		}
+		text = cleaned
+	}
+
+	text = wikiFileLink.ReplaceAllString(text, "")
+	text = wikiPipedLink.ReplaceAllString(text, "$1")
+	text = wikiLink.ReplaceAllString(text, "$1")
+	text = wikiExternal.ReplaceAllString(text, "$1")
+	text = wikiBreak.ReplaceAllString(text, "\n")
+	text = wikiTag.ReplaceAllString(text, "")
+	text = wikiEmphasis.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = wikiSpaces.ReplaceAllString(text, " ")
+	text = wikiLineSpacing.ReplaceAllString(text, "\n")
+
+	return strings.TrimSpace(text)
+}
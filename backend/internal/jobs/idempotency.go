@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"quotes-backend/internal/repository"
+)
+
+// idempotencyPurgeInterval - периодичность удаления устаревших ключей идемпотентности
+const idempotencyPurgeInterval = time.Hour
+
+// StartIdempotencyPurger запускает фоновое удаление ключей идемпотентности с истекшим сроком хранения
+func StartIdempotencyPurger(repo repository.IdempotencyRepository) {
+	go func() {
+		ticker := time.NewTicker(idempotencyPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			deleted, err := repo.DeleteExpired(time.Now())
+			if err != nil {
+				log.Printf("Failed to purge idempotency keys: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Purged %d expired idempotency keys", deleted)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"quotes-backend/internal/repository"
+)
+
+// trashPurgeInterval - периодичность проверки корзины на устаревшие цитаты
+const trashPurgeInterval = time.Hour
+
+// StartTrashPurger запускает фоновую очистку корзины
+// Цитаты, пролежавшие в корзине дольше retention, удаляются окончательно
+// При retention <= 0 автоматическая очистка отключена
+func StartTrashPurger(repo repository.QuoteRepository, retention time.Duration) {
+	if retention <= 0 {
+		log.Println("Trash auto-purge is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := repo.PurgeDeletedBefore(time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("Failed to purge trash: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d quotes from trash", purged)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
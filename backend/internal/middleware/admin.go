@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth проверяет, что запрос содержит заголовок Authorization: Bearer <ADMIN_PASSWORD>
+// Используется для защиты административных эндпоинтов
+func AdminAuth(password string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdminRequest(c, password) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		c.Set(adminContextKey, true)
+		c.Next()
+	}
+}
+
+// adminContextKey - ключ контекста, в котором DetectAdmin отмечает запросы администратора
+const adminContextKey = "is_admin"
+
+// DetectAdmin отмечает запросы с токеном администратора, не отклоняя остальные
+// Используется на публичных эндпоинтах, поведение которых отличается для администратора
+func DetectAdmin(password string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isAdminRequest(c, password) {
+			c.Set(adminContextKey, true)
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin проверяет, что запрос выполнен администратором (см. DetectAdmin и AdminAuth)
+func IsAdmin(c *gin.Context) bool {
+	return c.GetBool(adminContextKey)
+}
+
+// isAdminRequest сравнивает токен из заголовка с паролем администратора за постоянное время
+func isAdminRequest(c *gin.Context, password string) bool {
+	if password == "" {
+		return false
+	}
+
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(password)) == 1
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// noStorePolicy запрещает кэширование ответов администратору: они содержат черновики и скрытые цитаты
+const noStorePolicy = "private, no-store"
+
+// Personalized проверяет, нужно ли заполнять в ответе поле is_liked для текущего клиента
+// С ?personalized=false поле всегда false, а ответ одинаков для всех клиентов и может кэшироваться CDN;
+// лайки клиента в этом случае запрашиваются отдельно (GET /api/quotes/likes)
+func Personalized(c *gin.Context) bool {
+	return c.Query("personalized") != "false"
+}
+
+// bufferedWriter задерживает ответ обработчика, чтобы вычислить ETag до отправки
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+// HTTPCache добавляет к успешным GET ответам валидаторы и заголовок Cache-Control
+// ETag вычисляется по телу ответа; если обработчик уже установил ETag (версия цитаты, см. If-Match),
+// хеш тела добавляется к нему: "v3" -> "v3-<хеш>". Last-Modified устанавливает сам обработчик
+// На If-None-Match (или If-Modified-Since без него) при совпадении возвращается 304 без тела
+// policy применяется к ответам, одинаковым для всех клиентов, personalizedPolicy - к ответам
+// с заполненным полем is_liked (см. Personalized); ответы администратору не кэшируются
+func HTTPCache(policy, personalizedPolicy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.status != http.StatusOK {
+			original.WriteHeader(writer.status)
+			original.Write(writer.body.Bytes())
+			return
+		}
+
+		header := original.Header()
+		switch {
+		case IsAdmin(c):
+			header.Set("Cache-Control", noStorePolicy)
+		case Personalized(c):
+			header.Set("Cache-Control", personalizedPolicy)
+		default:
+			header.Set("Cache-Control", policy)
+		}
+		// Ответ администратору отличается от публичного
+		header.Add("Vary", "Authorization")
+
+		digest := sha256.New()
+		digest.Write(writer.body.Bytes())
+		if format := NegotiatedFormat(c); format != FormatJSON {
+			// Тело буферизуется до перекодирования (см. Negotiate): представления в других форматах
+			// должны получить собственные ETag
+			digest.Write([]byte(format))
+		}
+		hash := hex.EncodeToString(digest.Sum(nil)[:8])
+		etag := `"` + hash + `"`
+		if current := header.Get("ETag"); current != "" {
+			etag = strings.TrimSuffix(current, `"`) + "-" + hash + `"`
+		}
+		header.Set("ETag", etag)
+
+		if notModified(c.Request, etag, header.Get("Last-Modified")) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+
+		original.WriteHeader(http.StatusOK)
+		original.Write(writer.body.Bytes())
+	}
+}
+
+// notModified проверяет условные заголовки запроса
+// If-None-Match сравнивается слабым сравнением и имеет приоритет над If-Modified-Since
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		if strings.TrimSpace(match) == "*" {
+			return true
+		}
+		for _, candidate := range strings.Split(match, ",") {
+			if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since := r.Header.Get("If-Modified-Since")
+	if since == "" || lastModified == "" {
+		return false
+	}
+	sinceTime, err := http.ParseTime(since)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(sinceTime)
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"quotes-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader - заголовок с ключом идемпотентности, который клиент генерирует для каждой операции
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader отмечает ответ, возвращенный из сохраненного результата
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength - максимальная длина ключа идемпотентности
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotentBodySize - максимальный размер тела запроса, для которого вычисляется отпечаток
+const maxIdempotentBodySize = 1 << 20
+
+// IdempotencyStore хранит ключи идемпотентности и ответы (см. repository.IdempotencyRepository)
+type IdempotencyStore interface {
+	Reserve(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	Complete(key string, statusCode int, contentType string, body []byte) error
+	Release(key string) error
+}
+
+// responseRecorder дублирует тело ответа для сохранения
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency обрабатывает заголовок Idempotency-Key
+// Первый запрос с ключом выполняется, его ответ сохраняется на ttl; повтор с тем же ключом и телом
+// возвращает сохраненный ответ без повторного выполнения. Повтор с другим телом или на другой путь
+// возвращает 422, повтор во время выполнения первого запроса - 409
+// Ключи разных клиентов (администратора и посетителей с разными IP) не пересекаются
+// Ответы 5xx и 429 не сохраняются, чтобы запрос можно было повторить
+// Запросы без заголовка обрабатываются как обычно
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Ключ действует только для клиента, который его отправил, поэтому чужой ключ не вернет чужой ответ
+		key = scopedIdempotencyKey(c, key)
+		record := &models.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: requestFingerprint(c.Request.Method, c.Request.URL.Path, body),
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+
+		existing, err := store.Reserve(record)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request"})
+			case !existing.Completed:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "request with this Idempotency-Key is still in progress"})
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		// Ключ освобождается, если ответ не был сохранен: при ошибке, панике обработчика
+		// или сбое сохранения; иначе повторы получали бы 409 до истечения ttl
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := store.Release(key); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			return
+		}
+
+		if err := store.Complete(key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			log.Printf("Failed to save idempotent response: %v", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// scopedIdempotencyKey возвращает ключ хранения: хэш ключа клиента вместе с его идентичностью
+// (администратор или IP адрес), чтобы одинаковые ключи разных клиентов не пересекались
+func scopedIdempotencyKey(c *gin.Context, key string) string {
+	client := "ip:" + c.ClientIP()
+	if IsAdmin(c) {
+		client = "admin"
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(client))
+	hash.Write([]byte{0})
+	hash.Write([]byte(key))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// requestFingerprint вычисляет отпечаток запроса по методу, пути и телу
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
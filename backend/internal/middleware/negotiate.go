@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"quotes-backend/internal/formats"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// Форматы ответов API
+const (
+	FormatJSON    = "json"
+	FormatXML     = "xml"
+	FormatText    = "text"
+	FormatMsgPack = "msgpack"
+)
+
+// formatContextKey - ключ контекста с форматом ответа
+const formatContextKey = "response_format"
+
+// offeredTypes - поддерживаемые типы содержимого в порядке предпочтения сервера
+var offeredTypes = []struct {
+	mediaType string
+	format    string
+}{
+	{"application/json", FormatJSON},
+	{"application/xml", FormatXML},
+	{"text/xml", FormatXML},
+	{"text/plain", FormatText},
+	{"application/msgpack", FormatMsgPack},
+	{"application/x-msgpack", FormatMsgPack},
+}
+
+// Negotiate выбирает формат ответа по заголовку Accept (с учетом q) и перекодирует в него JSON ответы обработчиков
+// Поддерживаются JSON, XML, простой текст ("текст — автор") и MessagePack; если ни один тип не подходит,
+// ответ остается в JSON. Ответы не в JSON (файлы выгрузки) и вложения передаются без изменений
+// Простой текст доступен только для цитат и ошибок, остальные ответы в этом случае возвращаются в JSON
+func Negotiate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format, mediaType := negotiateFormat(c.GetHeader("Accept"))
+		c.Set(formatContextKey, format)
+		// Представление ответа зависит от Accept
+		c.Writer.Header().Add("Vary", "Accept")
+
+		if format == FormatJSON {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &negotiatedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		switch writer.mode {
+		case modeDirect:
+			return
+		case modeUndecided:
+			// Ответ без тела (204, 304)
+			original.WriteHeader(writer.status)
+			return
+		}
+
+		body := writer.body.Bytes()
+		value, err := formats.Decode(body)
+		if err != nil {
+			writeBuffered(original, writer.status, body)
+			return
+		}
+
+		switch format {
+		case FormatXML:
+			data, err := formats.XML(value)
+			if err != nil {
+				writeBuffered(original, writer.status, body)
+				return
+			}
+			original.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+			writeBuffered(original, writer.status, data)
+		case FormatText:
+			data, ok := formats.Text(value)
+			if !ok {
+				writeBuffered(original, writer.status, body)
+				return
+			}
+			original.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+			writeBuffered(original, writer.status, data)
+		case FormatMsgPack:
+			var buf bytes.Buffer
+			if err := render.WriteMsgPack(&bufferResponse{header: http.Header{}, body: &buf}, formats.Plain(value)); err != nil {
+				writeBuffered(original, writer.status, body)
+				return
+			}
+			original.Header().Set("Content-Type", mediaType)
+			writeBuffered(original, writer.status, buf.Bytes())
+		}
+	}
+}
+
+// NegotiatedFormat возвращает формат ответа, выбранный Negotiate (FormatJSON без middleware)
+func NegotiatedFormat(c *gin.Context) string {
+	if format := c.GetString(formatContextKey); format != "" {
+		return format
+	}
+	return FormatJSON
+}
+
+// negotiateFormat выбирает формат с наибольшим q среди поддерживаемых
+// q типа определяется самым точным подходящим диапазоном: text/plain, затем text/*, затем */*
+// При равных q предпочтение отдается порядку offeredTypes; пустой Accept означает JSON
+func negotiateFormat(accept string) (format, mediaType string) {
+	if strings.TrimSpace(accept) == "" {
+		return FormatJSON, offeredTypes[0].mediaType
+	}
+
+	type acceptRange struct {
+		mediaType string
+		q         float64
+	}
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		r := acceptRange{mediaType: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					r.q = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	best, bestQ := -1, 0.0
+	for i, offered := range offeredTypes {
+		mainType, _, _ := strings.Cut(offered.mediaType, "/")
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			s := -1
+			switch r.mediaType {
+			case offered.mediaType:
+				s = 2
+			case mainType + "/*":
+				s = 1
+			case "*/*":
+				s = 0
+			}
+			if s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+
+	if best < 0 {
+		return FormatJSON, offeredTypes[0].mediaType
+	}
+	return offeredTypes[best].format, offeredTypes[best].mediaType
+}
+
+// Режимы negotiatedWriter; решение принимается при первой записи по заголовку Content-Type
+const (
+	modeUndecided = iota
+	modeBuffered  // JSON ответ накапливается для перекодирования
+	modeDirect    // Остальные ответы передаются клиенту без изменений
+)
+
+// negotiatedWriter накапливает JSON ответ обработчика, чтобы перекодировать его в выбранный формат
+type negotiatedWriter struct {
+	gin.ResponseWriter
+	status int
+	mode   int
+	body   bytes.Buffer
+}
+
+// decide выбирает режим по заголовкам ответа
+func (w *negotiatedWriter) decide() {
+	if w.mode != modeUndecided {
+		return
+	}
+	header := w.Header()
+	if strings.HasPrefix(header.Get("Content-Type"), "application/json") && header.Get("Content-Disposition") == "" {
+		w.mode = modeBuffered
+		return
+	}
+	w.mode = modeDirect
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *negotiatedWriter) WriteHeader(code int) {
+	if w.mode == modeDirect {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+}
+
+func (w *negotiatedWriter) WriteHeaderNow() {
+	w.decide()
+	if w.mode == modeDirect {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *negotiatedWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.mode == modeDirect {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *negotiatedWriter) WriteString(s string) (int, error) {
+	w.decide()
+	if w.mode == modeDirect {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+func (w *negotiatedWriter) Status() int {
+	if w.mode == modeDirect {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *negotiatedWriter) Size() int {
+	if w.mode == modeDirect {
+		return w.ResponseWriter.Size()
+	}
+	return w.body.Len()
+}
+
+func (w *negotiatedWriter) Written() bool {
+	if w.mode == modeDirect {
+		return w.ResponseWriter.Written()
+	}
+	return w.mode == modeBuffered
+}
+
+func (w *negotiatedWriter) Flush() {
+	if w.mode == modeDirect {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// writeBuffered отправляет накопленный ответ клиенту
+func writeBuffered(w gin.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// bufferResponse - http.ResponseWriter, записывающий тело в буфер (для кодировщиков gin/render)
+type bufferResponse struct {
+	header http.Header
+	body   *bytes.Buffer
+}
+
+func (w *bufferResponse) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferResponse) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferResponse) WriteHeader(int) {}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow хранит количество запросов клиента в текущем окне
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter подсчитывает запросы клиентов в фиксированных окнах времени
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	clients   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+// RateLimit ограничивает количество запросов от одного клиента (по IP) до limit за window
+// При превышении лимита возвращается 429 Too Many Requests с заголовком Retry-After
+// Запросы администратора (см. DetectAdmin) не ограничиваются; limit <= 0 отключает ограничение
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 || window <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	limiter := &rateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*rateWindow),
+	}
+
+	return func(c *gin.Context) {
+		if IsAdmin(c) {
+			c.Next()
+			return
+		}
+
+		if retryAfter, ok := limiter.allow(c.ClientIP(), time.Now()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds()+0.5)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, try again later"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// allow учитывает запрос клиента и проверяет лимит
+// Если лимит исчерпан, возвращает время до начала следующего окна
+func (l *rateLimiter) allow(client string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Периодически удаляем окна, которые уже закончились, чтобы карта не росла бесконечно
+	if now.Sub(l.lastSweep) > l.window {
+		for key, w := range l.clients {
+			if now.Sub(w.start) >= l.window {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.clients[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		l.clients[client] = &rateWindow{start: now, count: 1}
+		return 0, true
+	}
+
+	if w.count >= l.limit {
+		return w.start.Add(l.window).Sub(now), false
+	}
+
+	w.count++
+	return 0, true
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"quotes-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// shapeContextKey - ключ контекста с параметрами формы ответа
+const shapeContextKey = "response_shape"
+
+// ResponseShape описывает поля и связанные данные, запрошенные клиентом для ответов с цитатами
+type ResponseShape struct {
+	Fields  map[string]bool // Поля цитаты из параметра fields; nil (параметр не указан или пуст) - все поля
+	Include map[string]bool // Связанные данные из параметра include (models.IncludeStats, models.IncludeAuthor)
+}
+
+// Shape разбирает параметры fields и include (значения через запятую) и сохраняет их в контексте
+// Неизвестные поля и связанные данные отклоняются с 400 до выполнения обработчика
+func Shape() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var shape ResponseShape
+
+		if raw := c.Query("fields"); strings.TrimSpace(raw) != "" {
+			shape.Fields = make(map[string]bool)
+			for _, field := range splitList(raw) {
+				if !models.IsQuoteResponseField(field) {
+					c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unknown field " + field})
+					return
+				}
+				shape.Fields[field] = true
+			}
+		}
+
+		for _, include := range splitList(c.Query("include")) {
+			if include == "tags" {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "include=tags is not supported: quotes have no tags"})
+				return
+			}
+			if !models.IsValidInclude(include) {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unknown include " + include + ", expected stats or author"})
+				return
+			}
+			if shape.Include == nil {
+				shape.Include = make(map[string]bool)
+			}
+			shape.Include[include] = true
+		}
+
+		c.Set(shapeContextKey, shape)
+		c.Next()
+	}
+}
+
+// Unshaped отклоняет параметры fields и include с 400 на роутах, ответы которых не состоят из цитат
+// (группы дубликатов, жалобы, ревизии), чтобы клиент не получал молча полный ответ
+func Unshaped() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(splitList(c.Query("fields"))) > 0 || len(splitList(c.Query("include"))) > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "fields and include are not supported by this endpoint"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetShape возвращает параметры формы ответа (см. Shape)
+// Без middleware Shape возвращает пустую форму: все поля без связанных данных
+func GetShape(c *gin.Context) ResponseShape {
+	shape, _ := c.Get(shapeContextKey)
+	s, _ := shape.(ResponseShape)
+	return s
+}
+
+// splitList разбирает список значений через запятую, пропуская пустые
+func splitList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
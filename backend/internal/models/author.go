@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthorAlias представляет альтернативное написание имени автора
+// Например, "Steve Jobs" и "Jobs" для автора "Стив Джобс"
+type AuthorAlias struct {
+	ID        string    `json:"id" db:"id"`
+	Author    string    `json:"author" db:"author"`
+	Alias     string    `json:"alias" db:"alias"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateAuthorAliasRequest представляет запрос на создание псевдонима автора
+type CreateAuthorAliasRequest struct {
+	Author string `json:"author" binding:"required"`
+	Alias  string `json:"alias" binding:"required"`
+}
+
+// NewAuthorAlias создает новый псевдоним автора из запроса
+func NewAuthorAlias(req CreateAuthorAliasRequest) *AuthorAlias {
+	return &AuthorAlias{
+		ID:     uuid.New().String(),
+		Author: req.Author,
+		Alias:  req.Alias,
+	}
+}
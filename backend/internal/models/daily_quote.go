@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// DailyQuote - цитата дня, закрепленная за датой
+type DailyQuote struct {
+	Date       time.Time // Дата (полночь UTC)
+	Quote      Quote
+	AssignedAt time.Time // Время закрепления цитаты за датой
+}
@@ -0,0 +1,44 @@
+package models
+
+// DuplicateQuote - существующая цитата, совпадающая с проверяемым текстом
+type DuplicateQuote struct {
+	Quote      Quote
+	Similarity float64 // Триграммная похожесть текста (0..1)
+	Exact      bool    // Совпадает нормализованный текст
+	Visible    bool    // Цитата опубликована и видна посетителям
+}
+
+// DuplicateQuoteResponse представляет дубликат для API
+type DuplicateQuoteResponse struct {
+	QuoteResponse
+	Similarity float64 `json:"similarity"`
+	Exact      bool    `json:"exact"`
+}
+
+// ToResponse преобразует дубликат в DuplicateQuoteResponse
+func (d DuplicateQuote) ToResponse() DuplicateQuoteResponse {
+	return DuplicateQuoteResponse{
+		QuoteResponse: d.Quote.ToResponse(false),
+		Similarity:    d.Similarity,
+		Exact:         d.Exact,
+	}
+}
+
+// DuplicateConflictResponse возвращается со статусом 409, если создаваемая цитата уже существует
+// Посетителю возвращаются только опубликованные дубликаты, администратору - все
+type DuplicateConflictResponse struct {
+	Error      string                   `json:"error"`
+	Duplicates []DuplicateQuoteResponse `json:"duplicates,omitempty"`
+}
+
+// DuplicateClusterResponse - группа цитат, являющихся дубликатами друг друга
+type DuplicateClusterResponse struct {
+	Quotes []DuplicateQuoteResponse `json:"quotes"`
+}
+
+// MergeQuotesRequest представляет запрос на объединение дубликатов
+// Цитаты source_ids перемещаются в корзину, их лайки переносятся на target_id
+type MergeQuotesRequest struct {
+	TargetID  string   `json:"target_id" binding:"required"`
+	SourceIDs []string `json:"source_ids" binding:"required,min=1"`
+}
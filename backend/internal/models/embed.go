@@ -0,0 +1,51 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Связанные данные, которые можно встроить в ответ с цитатой параметром include
+const (
+	IncludeStats  = "stats"  // Статистика цитаты (QuoteStats)
+	IncludeAuthor = "author" // Сведения об авторе (AuthorDetails)
+)
+
+// QuoteStats содержит статистику цитаты
+type QuoteStats struct {
+	LikesCount     int `json:"likes_count"`
+	LikesWeek      int `json:"likes_week"` // Лайки за последние 7 дней
+	RevisionsCount int `json:"revisions_count"`
+}
+
+// AuthorDetails содержит сведения об авторе цитаты
+type AuthorDetails struct {
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases"`      // Псевдонимы и варианты написания имени
+	QuotesCount int      `json:"quotes_count"` // Количество опубликованных цитат автора
+}
+
+// IsValidInclude проверяет, что связанные данные поддерживаются параметром include
+func IsValidInclude(include string) bool {
+	return include == IncludeStats || include == IncludeAuthor
+}
+
+// quoteResponseFields - имена полей QuoteResponse в JSON
+var quoteResponseFields = jsonFieldNames(reflect.TypeOf(QuoteResponse{}))
+
+// IsQuoteResponseField проверяет, что поле есть в ответе с цитатой (для параметра fields)
+func IsQuoteResponseField(field string) bool {
+	return quoteResponseFields[field]
+}
+
+// jsonFieldNames возвращает имена полей структуры из тегов json
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.SplitN(t.Field(i).Tag.Get("json"), ",", 2)[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
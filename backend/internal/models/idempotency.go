@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// IdempotencyRecord - сохраненный результат запроса с заголовком Idempotency-Key
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string // Хеш метода, пути и тела запроса
+	Completed   bool   // Ответ сохранен; false - запрос еще выполняется
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
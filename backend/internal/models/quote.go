@@ -6,37 +6,169 @@ import (
 	"github.com/google/uuid"
 )
 
+// Статусы атрибуции цитаты
+const (
+	AttributionVerified      = "verified"      // Авторство подтверждено источником
+	AttributionDisputed      = "disputed"      // Авторство оспаривается
+	AttributionMisattributed = "misattributed" // Цитата ошибочно приписывается автору
+	AttributionUnknown       = "unknown"       // Авторство не проверялось
+)
+
+// IsValidAttributionStatus проверяет, что статус атрибуции входит в список допустимых
+func IsValidAttributionStatus(status string) bool {
+	switch status {
+	case AttributionVerified, AttributionDisputed, AttributionMisattributed, AttributionUnknown:
+		return true
+	}
+	return false
+}
+
+// Статусы цитаты
+const (
+	StatusDraft    = "draft"    // Черновик редактора, не публикуется
+	StatusPending  = "pending"  // Предложена посетителем и ожидает проверки
+	StatusApproved = "approved" // Опубликована (или будет опубликована в publish_at)
+	StatusRejected = "rejected" // Отклонена модератором
+)
+
+// IsValidStatus проверяет, что статус цитаты входит в список допустимых
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusDraft, StatusPending, StatusApproved, StatusRejected:
+		return true
+	}
+	return false
+}
+
+// UnknownAuthor используется для цитат, автор которых не указан в источнике
+const UnknownAuthor = "Неизвестный автор"
+
 // Quote представляет цитату в системе
 type Quote struct {
-	ID         string    `json:"id" db:"id"`
-	Text       string    `json:"text" db:"text"`
-	Author     string    `json:"author" db:"author"`
-	LikesCount int       `json:"likes_count" db:"likes_count"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ID                string       `json:"id" db:"id"`
+	Text              string       `json:"text" db:"text"`
+	Author            string       `json:"author" db:"author"`
+	LikesCount        int          `json:"likes_count" db:"likes_count"`
+	Source            *QuoteSource `json:"source,omitempty"`
+	AttributionStatus string       `json:"attribution_status" db:"attribution_status"`
+	AttributionNote   string       `json:"attribution_note,omitempty" db:"attribution_note"`
+	Status            string       `json:"status" db:"status"`                               // Статус модерации
+	RejectionReason   string       `json:"rejection_reason,omitempty" db:"rejection_reason"` // Причина отклонения
+	ModeratedAt       *time.Time   `json:"moderated_at,omitempty" db:"moderated_at"`         // Время решения модератора
+	PublishAt         *time.Time   `json:"publish_at,omitempty" db:"publish_at"`             // Время отложенной публикации
+	CreatedAt         time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at" db:"updated_at"`
+	DeletedAt         *time.Time   `json:"deleted_at,omitempty" db:"deleted_at"` // Время перемещения в корзину
+	Version           int          `json:"version" db:"version"`                 // Увеличивается при каждом изменении цитаты
+}
+
+// QuoteSource содержит сведения об источнике цитаты
+// В базе данных хранится в колонках source_* таблицы quotes
+type QuoteSource struct {
+	Title   string `json:"title,omitempty" db:"source_title"`
+	Year    *int   `json:"year,omitempty" db:"source_year" binding:"omitempty,min=-3000,max=3000"`
+	Page    string `json:"page,omitempty" db:"source_page" binding:"max=50"`
+	URL     string `json:"url,omitempty" db:"source_url" binding:"omitempty,url"`
+	Context string `json:"context,omitempty" db:"source_context"` // Фрагмент текста вокруг цитаты
+}
+
+// IsEmpty проверяет, что ни одно поле источника не заполнено
+func (s *QuoteSource) IsEmpty() bool {
+	return s == nil || (s.Title == "" && s.Year == nil && s.Page == "" && s.URL == "" && s.Context == "")
 }
 
 // CreateQuoteRequest представляет запрос на создание цитаты
 type CreateQuoteRequest struct {
-	Text   string `json:"text" binding:"required"`
-	Author string `json:"author" binding:"required"`
+	Text              string       `json:"text" binding:"required"`
+	Author            string       `json:"author" binding:"required"`
+	Source            *QuoteSource `json:"source"`
+	AttributionStatus string       `json:"attribution_status" binding:"omitempty,oneof=verified disputed misattributed unknown"`
+	AttributionNote   string       `json:"attribution_note"`
+	Status            string       `json:"status" binding:"omitempty,oneof=draft approved"` // Только для администратора
+	PublishAt         *time.Time   `json:"publish_at"`                                      // Только для администратора
 }
 
 // UpdateQuoteRequest представляет запрос на обновление цитаты
+// Source, если передан, заменяет сведения об источнике целиком
 type UpdateQuoteRequest struct {
-	Text   string `json:"text"`
-	Author string `json:"author"`
+	Text              string       `json:"text"`
+	Author            string       `json:"author"`
+	Source            *QuoteSource `json:"source"`
+	AttributionStatus string       `json:"attribution_status" binding:"omitempty,oneof=verified disputed misattributed unknown"`
+	AttributionNote   *string      `json:"attribution_note"`
+	Status            string       `json:"status" binding:"omitempty,oneof=draft approved"` // Только для администратора
+	PublishAt         *time.Time   `json:"publish_at"`                                      // Только для администратора
+}
+
+// QuoteDocument - редактируемые поля цитаты, к которым применяется PATCH
+// В отличие от UpdateQuoteRequest, пустое значение поля означает очистку, а не «без изменений»
+type QuoteDocument struct {
+	Text              string       `json:"text" binding:"required"`
+	Author            string       `json:"author" binding:"required"`
+	Source            *QuoteSource `json:"source"`
+	AttributionStatus string       `json:"attribution_status" binding:"oneof=verified disputed misattributed unknown"`
+	AttributionNote   string       `json:"attribution_note"`
+	Status            string       `json:"status" binding:"oneof=draft approved"` // Изменение только для администратора
+	PublishAt         *time.Time   `json:"publish_at"`                            // Изменение только для администратора
+}
+
+// Document возвращает редактируемые поля цитаты
+func (q *Quote) Document() QuoteDocument {
+	return QuoteDocument{
+		Text:              q.Text,
+		Author:            q.Author,
+		Source:            q.Source,
+		AttributionStatus: q.AttributionStatus,
+		AttributionNote:   q.AttributionNote,
+		Status:            q.Status,
+		PublishAt:         q.PublishAt,
+	}
+}
+
+// ApplyDocument переносит редактируемые поля в цитату
+func (q *Quote) ApplyDocument(d QuoteDocument) {
+	q.Text = d.Text
+	q.Author = d.Author
+	q.Source = d.Source
+	q.AttributionStatus = d.AttributionStatus
+	q.AttributionNote = d.AttributionNote
+	q.Status = d.Status
+	q.PublishAt = d.PublishAt
+}
+
+// ScheduledDay - цитаты, запланированные к публикации на один день
+type ScheduledDay struct {
+	Date   string          `json:"date"` // Дата в формате YYYY-MM-DD
+	Quotes []QuoteResponse `json:"quotes"`
+}
+
+// RejectQuoteRequest представляет запрос на отклонение предложенной цитаты
+type RejectQuoteRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
 }
 
 // QuoteResponse представляет ответ API с цитатой
 type QuoteResponse struct {
-	ID         string    `json:"id"`
-	Text       string    `json:"text"`
-	Author     string    `json:"author"`
-	LikesCount int       `json:"likes_count"`
-	IsLiked    bool      `json:"is_liked"` // Информация о том, лайкнул ли текущий пользователь эту цитату
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID                string       `json:"id"`
+	Text              string       `json:"text"`
+	Author            string       `json:"author"`
+	LikesCount        int          `json:"likes_count"`
+	IsLiked           bool         `json:"is_liked"` // Информация о том, лайкнул ли текущий пользователь эту цитату
+	Source            *QuoteSource `json:"source,omitempty"`
+	AttributionStatus string       `json:"attribution_status"`
+	AttributionNote   string       `json:"attribution_note,omitempty"`
+	Status            string       `json:"status"`
+	RejectionReason   string       `json:"rejection_reason,omitempty"`
+	ModeratedAt       *time.Time   `json:"moderated_at,omitempty"`
+	PublishAt         *time.Time   `json:"publish_at,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+	DeletedAt         *time.Time   `json:"deleted_at,omitempty"`
+	Version           int          `json:"version"`
+
+	// Заполняются, только если запрошены параметром include
+	Stats         *QuoteStats    `json:"stats,omitempty"`
+	AuthorDetails *AuthorDetails `json:"author_details,omitempty"`
 }
 
 // PaginatedQuotesResponse представляет ответ API с пагинацией
@@ -48,26 +180,86 @@ type PaginatedQuotesResponse struct {
 	TotalPages  int             `json:"total_pages"`
 }
 
+// BatchQuotesResponse содержит цитаты в порядке запроса и ID, которые не удалось найти
+type BatchQuotesResponse struct {
+	Quotes  []QuoteResponse `json:"quotes"`
+	Missing []string        `json:"missing"` // Удаленные, скрытые или несуществующие цитаты
+}
+
+// LikedQuotesResponse содержит ID цитат, лайкнутых текущим пользователем
+type LikedQuotesResponse struct {
+	Liked []string `json:"liked"`
+}
+
+// QuoteFilter содержит параметры фильтрации списка цитат
+type QuoteFilter struct {
+	Search string // Поиск по тексту, автору и псевдонимам автора
+	Author string // Фильтр по автору с учетом псевдонимов и транслитерации
+
+	AttributionStatus string // Фильтр по статусу атрибуции
+	HasSource         *bool  // Фильтр по наличию источника
+}
+
+// PublishedAt возвращает время, когда цитата стала видна посетителям:
+// самое позднее из времени создания, одобрения модератором и отложенной публикации
+func (q *Quote) PublishedAt() time.Time {
+	published := q.CreatedAt
+	if q.ModeratedAt != nil && q.ModeratedAt.After(published) {
+		published = *q.ModeratedAt
+	}
+	if q.PublishAt != nil && q.PublishAt.After(published) {
+		published = *q.PublishAt
+	}
+	return published
+}
+
 // ToResponse преобразует Quote в QuoteResponse
 // isLiked указывает, лайкнул ли текущий пользователь эту цитату
 func (q *Quote) ToResponse(isLiked bool) QuoteResponse {
 	return QuoteResponse{
-		ID:         q.ID,
-		Text:       q.Text,
-		Author:     q.Author,
-		LikesCount: q.LikesCount,
-		IsLiked:    isLiked,
-		CreatedAt:  q.CreatedAt,
-		UpdatedAt:  q.UpdatedAt,
+		ID:                q.ID,
+		Text:              q.Text,
+		Author:            q.Author,
+		LikesCount:        q.LikesCount,
+		IsLiked:           isLiked,
+		Source:            q.Source,
+		AttributionStatus: q.AttributionStatus,
+		AttributionNote:   q.AttributionNote,
+		Status:            q.Status,
+		RejectionReason:   q.RejectionReason,
+		ModeratedAt:       q.ModeratedAt,
+		PublishAt:         q.PublishAt,
+		CreatedAt:         q.CreatedAt,
+		UpdatedAt:         q.UpdatedAt,
+		DeletedAt:         q.DeletedAt,
+		Version:           q.Version,
 	}
 }
 
 // NewQuote создает новую цитату из запроса
 func NewQuote(req CreateQuoteRequest) *Quote {
-	return &Quote{
-		ID:     uuid.New().String(),
-		Text:   req.Text,
-		Author: req.Author,
+	status := req.AttributionStatus
+	if status == "" {
+		status = AttributionUnknown
 	}
+
+	quote := &Quote{
+		ID:                uuid.New().String(),
+		Text:              req.Text,
+		Author:            req.Author,
+		AttributionStatus: status,
+		AttributionNote:   req.AttributionNote,
+		Status:            StatusApproved,
+		PublishAt:         req.PublishAt,
+		Version:           1,
+	}
+	if req.Status != "" {
+		quote.Status = req.Status
+	}
+	if !req.Source.IsEmpty() {
+		quote.Source = req.Source
+	}
+
+	return quote
 }
 
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Причины жалоб на цитату
+const (
+	ReportOffensive     = "offensive"     // Оскорбительное содержание
+	ReportIncorrect     = "incorrect"     // Ошибка в тексте цитаты
+	ReportMisattributed = "misattributed" // Неверно указан автор
+	ReportDuplicate     = "duplicate"     // Дубликат другой цитаты
+	ReportSpam          = "spam"          // Спам или реклама
+	ReportOther         = "other"         // Другое (подробности в комментарии)
+)
+
+// Решения по жалобам
+const (
+	ResolutionDismiss = "dismiss" // Жалобы необоснованны, цитата снова отображается
+	ResolutionHide    = "hide"    // Цитата остается скрытой
+	ResolutionDelete  = "delete"  // Цитата перемещается в корзину
+)
+
+// QuoteReport представляет жалобу посетителя на цитату
+type QuoteReport struct {
+	ID             string     `json:"id"`
+	QuoteID        string     `json:"quote_id"`
+	Reason         string     `json:"reason"`
+	Comment        string     `json:"comment,omitempty"`
+	ClientIP       string     `json:"client_ip"`
+	UserAgent      string     `json:"user_agent,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	Resolution     string     `json:"resolution,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty"`
+}
+
+// CreateReportRequest представляет запрос на жалобу
+type CreateReportRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=offensive incorrect misattributed duplicate spam other"`
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+// ReportCreatedResponse возвращается после приема жалобы
+type ReportCreatedResponse struct {
+	ID     string `json:"id"`
+	Hidden bool   `json:"hidden"` // Цитата скрыта, так как набрала достаточно жалоб
+}
+
+// ResolveReportsRequest представляет решение администратора по всем открытым жалобам на цитату
+type ResolveReportsRequest struct {
+	Action string `json:"action" binding:"required,oneof=dismiss hide delete"`
+	Note   string `json:"note" binding:"max=2000"`
+}
+
+// FlaggedQuote - цитата с открытыми жалобами
+type FlaggedQuote struct {
+	Quote          Quote
+	ReportCount    int
+	Reasons        map[string]int // Количество открытых жалоб по причинам
+	LastReportedAt time.Time
+	HiddenAt       *time.Time
+}
+
+// FlaggedQuoteResponse представляет цитату с жалобами для API
+type FlaggedQuoteResponse struct {
+	Quote          QuoteResponse  `json:"quote"`
+	ReportCount    int            `json:"report_count"`
+	Reasons        map[string]int `json:"reasons"`
+	LastReportedAt time.Time      `json:"last_reported_at"`
+	HiddenAt       *time.Time     `json:"hidden_at,omitempty"`
+}
+
+// ToResponse преобразует FlaggedQuote в FlaggedQuoteResponse
+func (f *FlaggedQuote) ToResponse() FlaggedQuoteResponse {
+	return FlaggedQuoteResponse{
+		Quote:          f.Quote.ToResponse(false),
+		ReportCount:    f.ReportCount,
+		Reasons:        f.Reasons,
+		LastReportedAt: f.LastReportedAt,
+		HiddenAt:       f.HiddenAt,
+	}
+}
+
+// NewQuoteReport создает новую жалобу из запроса
+func NewQuoteReport(quoteID string, req CreateReportRequest, clientIP, userAgent string) *QuoteReport {
+	return &QuoteReport{
+		ID:        uuid.New().String(),
+		QuoteID:   quoteID,
+		Reason:    req.Reason,
+		Comment:   req.Comment,
+		ClientIP:  clientIP,
+		UserAgent: userAgent,
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"quotes-backend/internal/textdiff"
+)
+
+// QuoteSnapshot содержит редактируемые поля цитаты на момент ревизии
+type QuoteSnapshot struct {
+	Text              string       `json:"text"`
+	Author            string       `json:"author"`
+	Source            *QuoteSource `json:"source,omitempty"`
+	AttributionStatus string       `json:"attribution_status"`
+	AttributionNote   string       `json:"attribution_note,omitempty"`
+}
+
+// Snapshot возвращает снимок редактируемых полей цитаты
+func (q *Quote) Snapshot() QuoteSnapshot {
+	return QuoteSnapshot{
+		Text:              q.Text,
+		Author:            q.Author,
+		Source:            q.Source,
+		AttributionStatus: q.AttributionStatus,
+		AttributionNote:   q.AttributionNote,
+	}
+}
+
+// ApplySnapshot переносит поля снимка в цитату
+func (q *Quote) ApplySnapshot(s QuoteSnapshot) {
+	q.Text = s.Text
+	q.Author = s.Author
+	q.Source = s.Source
+	q.AttributionStatus = s.AttributionStatus
+	q.AttributionNote = s.AttributionNote
+}
+
+// QuoteRevision представляет одно изменение цитаты
+// Old пустой для ревизии, созданной вместе с цитатой
+type QuoteRevision struct {
+	ID        string         `json:"id" db:"id"`
+	QuoteID   string         `json:"quote_id" db:"quote_id"`
+	Old       *QuoteSnapshot `json:"old,omitempty" db:"old_data"`
+	New       QuoteSnapshot  `json:"new" db:"new_data"`
+	Editor    string         `json:"editor" db:"editor"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// FieldChange описывает изменение одного поля цитаты
+// Для текстовых полей Diff содержит пословное различие
+type FieldChange struct {
+	Field string        `json:"field"`
+	Old   interface{}   `json:"old"`
+	New   interface{}   `json:"new"`
+	Diff  []textdiff.Op `json:"diff,omitempty"`
+}
+
+// QuoteRevisionResponse представляет ревизию цитаты со списком изменений
+type QuoteRevisionResponse struct {
+	QuoteRevision
+	Changes []FieldChange `json:"changes"`
+}
+
+// ToResponse преобразует QuoteRevision в QuoteRevisionResponse с вычисленными изменениями
+func (r *QuoteRevision) ToResponse() QuoteRevisionResponse {
+	return QuoteRevisionResponse{
+		QuoteRevision: *r,
+		Changes:       r.Changes(),
+	}
+}
+
+// Changes возвращает список полей, изменившихся в ревизии
+func (r *QuoteRevision) Changes() []FieldChange {
+	var old QuoteSnapshot
+	if r.Old != nil {
+		old = *r.Old
+	}
+
+	changes := []FieldChange{}
+	addText := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{
+				Field: field,
+				Old:   oldValue,
+				New:   newValue,
+				Diff:  textdiff.Diff(oldValue, newValue),
+			})
+		}
+	}
+	addValue := func(field string, oldValue, newValue interface{}) {
+		if fmt.Sprint(oldValue) != fmt.Sprint(newValue) {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	oldSource, newSource := sourceOrEmpty(old.Source), sourceOrEmpty(r.New.Source)
+
+	addText("text", old.Text, r.New.Text)
+	addText("author", old.Author, r.New.Author)
+	addText("source.title", oldSource.Title, newSource.Title)
+	addValue("source.year", yearValue(oldSource.Year), yearValue(newSource.Year))
+	addValue("source.page", oldSource.Page, newSource.Page)
+	addValue("source.url", oldSource.URL, newSource.URL)
+	addText("source.context", oldSource.Context, newSource.Context)
+	addValue("attribution_status", old.AttributionStatus, r.New.AttributionStatus)
+	addText("attribution_note", old.AttributionNote, r.New.AttributionNote)
+
+	return changes
+}
+
+// sourceOrEmpty возвращает источник или пустой источник вместо nil
+func sourceOrEmpty(s *QuoteSource) QuoteSource {
+	if s == nil {
+		return QuoteSource{}
+	}
+	return *s
+}
+
+// yearValue возвращает год источника или nil
+func yearValue(year *int) interface{} {
+	if year == nil {
+		return nil
+	}
+	return *year
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// SitemapPage - страница карты сайта с опубликованными цитатами
+// Страница содержит цитаты с ID не меньше FromID (пустой FromID - первая страница)
+type SitemapPage struct {
+	FromID       string
+	LastModified time.Time // Время последнего изменения цитат страницы
+}
+
+// SitemapEntry - опубликованная цитата в карте сайта
+type SitemapEntry struct {
+	ID        string
+	UpdatedAt time.Time
+}
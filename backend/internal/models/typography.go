@@ -0,0 +1,16 @@
+package models
+
+// TypographyPreviewRequest представляет запрос на предпросмотр нормализации типографики
+type TypographyPreviewRequest struct {
+	Text      string `json:"text" binding:"required"`
+	Language  string `json:"language" binding:"omitempty,oneof=ru en"` // Пустое значение - язык определяется по тексту
+	RestoreYo *bool  `json:"restore_yo"`                               // По умолчанию - значение TYPOGRAPHY_RESTORE_YO
+}
+
+// TypographyPreviewResponse содержит исходный и нормализованный текст
+type TypographyPreviewResponse struct {
+	Text       string `json:"text"`
+	Normalized string `json:"normalized"`
+	Language   string `json:"language"`
+	Changed    bool   `json:"changed"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// FieldError описывает ошибку в отдельном поле запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse представляет ответ API с ошибками проверки полей
+type ValidationErrorResponse struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
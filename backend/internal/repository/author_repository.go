@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"quotes-backend/internal/models"
+)
+
+// AuthorRepository определяет интерфейс для работы с псевдонимами авторов
+type AuthorRepository interface {
+	GetAliases(author string) ([]models.AuthorAlias, error)
+	CreateAlias(alias *models.AuthorAlias) error
+	DeleteAlias(id string) error
+}
+
+type authorRepository struct {
+	db *sql.DB
+}
+
+// NewAuthorRepository создает новый экземпляр репозитория авторов
+func NewAuthorRepository(db *sql.DB) AuthorRepository {
+	return &authorRepository{db: db}
+}
+
+// GetAliases возвращает псевдонимы авторов
+// Если author пустой, возвращаются все псевдонимы
+func (r *authorRepository) GetAliases(author string) ([]models.AuthorAlias, error) {
+	query := `SELECT id, author, alias, created_at FROM author_aliases`
+	args := []interface{}{}
+
+	if author != "" {
+		query += " WHERE author = $1"
+		args = append(args, author)
+	}
+	query += " ORDER BY author, alias"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := []models.AuthorAlias{}
+	for rows.Next() {
+		var alias models.AuthorAlias
+		if err := rows.Scan(&alias.ID, &alias.Author, &alias.Alias, &alias.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan author alias: %w", err)
+		}
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, nil
+}
+
+// CreateAlias создает новый псевдоним автора
+func (r *authorRepository) CreateAlias(alias *models.AuthorAlias) error {
+	query := `
+		INSERT INTO author_aliases (id, author, alias, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	alias.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(query, alias.ID, alias.Author, alias.Alias, alias.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return fmt.Errorf("alias already exists")
+		}
+		return fmt.Errorf("failed to create author alias: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAlias удаляет псевдоним автора
+func (r *authorRepository) DeleteAlias(id string) error {
+	result, err := r.db.Exec(`DELETE FROM author_aliases WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete author alias: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("alias not found")
+	}
+
+	return nil
+}
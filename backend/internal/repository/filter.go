@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"quotes-backend/internal/models"
+	"quotes-backend/internal/translit"
+
+	"github.com/lib/pq"
+)
+
+// likeEscaper экранирует спецсимволы шаблонов ILIKE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPatterns возвращает ILIKE шаблоны "%вариант%" для всех вариантов написания строки
+// Варианты включают исходную строку и ее транслитерацию (кириллица ⇄ латиница)
+func containsPatterns(s string) []string {
+	variants := translit.Variants(s)
+	patterns := make([]string, len(variants))
+	for i, v := range variants {
+		patterns[i] = "%" + likeEscaper.Replace(v) + "%"
+	}
+	return patterns
+}
+
+// visibleCondition отбирает опубликованные цитаты: одобренные, с наступившим временем публикации,
+// не скрытые по жалобам и не находящиеся в корзине
+const visibleCondition = "deleted_at IS NULL AND status = 'approved' AND hidden_at IS NULL AND (publish_at IS NULL OR publish_at <= NOW())"
+
+// editableCondition отбирает цитаты, доступные администратору для редактирования:
+// опубликованные, запланированные, черновики и скрытые по жалобам
+// Предложения посетителей редактируются через модерацию
+const editableCondition = "deleted_at IS NULL AND status IN ('approved', 'draft')"
+
+// existingCondition отбирает все цитаты, кроме находящихся в корзине
+const existingCondition = "deleted_at IS NULL"
+
+// activeCondition отбирает цитаты, которые могут стать дубликатами новой: опубликованные и ожидающие модерации
+const activeCondition = "deleted_at IS NULL AND status <> 'rejected'"
+
+// pendingCondition отбирает цитаты, ожидающие модерации
+const pendingCondition = "deleted_at IS NULL AND status = 'pending'"
+
+// authorCondition формирует условие поиска автора по шаблонам ILIKE из аргумента $n
+// Найденное имя (основное или псевдоним) раскрывается в группу: основное имя и все его псевдонимы,
+// поэтому цитаты, сохраненные под любым написанием имени, находятся по любому другому написанию
+func authorCondition(n int) string {
+	return fmt.Sprintf(`(author ILIKE ANY($%[1]d) OR lower(author) IN (
+		SELECT lower(names.name)
+		FROM author_aliases g
+		CROSS JOIN LATERAL (VALUES (g.author), (g.alias)) AS names(name)
+		WHERE g.author IN (SELECT author FROM author_aliases WHERE author ILIKE ANY($%[1]d) OR alias ILIKE ANY($%[1]d))
+	))`, n)
+}
+
+// buildQuoteFilter формирует WHERE условие и аргументы запроса по фильтру
+// Автор ищется по всем написаниям имени из его группы псевдонимов (см. authorCondition)
+// Цитаты из корзины и неопубликованные цитаты в выборку не попадают
+func buildQuoteFilter(filter models.QuoteFilter) (string, []interface{}) {
+	conditions := []string{visibleCondition}
+	var args []interface{}
+
+	if filter.Search != "" {
+		args = append(args, pq.Array(containsPatterns(filter.Search)))
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf(
+			"(text ILIKE ANY($%d) OR %s)",
+			n, authorCondition(n),
+		))
+	}
+
+	if filter.Author != "" {
+		args = append(args, pq.Array(containsPatterns(filter.Author)))
+		n := len(args)
+		conditions = append(conditions, authorCondition(n))
+	}
+
+	if filter.AttributionStatus != "" {
+		args = append(args, filter.AttributionStatus)
+		conditions = append(conditions, fmt.Sprintf("attribution_status = $%d", len(args)))
+	}
+
+	if filter.HasSource != nil {
+		// Источник считается указанным, если заполнено любое его поле (как в QuoteSource.IsEmpty)
+		hasSource := "(source_title IS NOT NULL OR source_year IS NOT NULL OR source_page IS NOT NULL" +
+			" OR source_url IS NOT NULL OR source_context IS NOT NULL)"
+		if *filter.HasSource {
+			conditions = append(conditions, hasSource)
+		} else {
+			conditions = append(conditions, "NOT "+hasSource)
+		}
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"quotes-backend/internal/models"
+)
+
+// IdempotencyRepository определяет интерфейс для хранения ключей идемпотентности
+type IdempotencyRepository interface {
+	Reserve(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	Complete(key string, statusCode int, contentType string, body []byte) error
+	Release(key string) error
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository создает новый экземпляр репозитория ключей идемпотентности
+func NewIdempotencyRepository(db *sql.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Reserve занимает ключ для выполнения запроса
+// Если ключ уже занят и не устарел, ничего не записывает и возвращает существующую запись;
+// если ключ свободен, возвращает nil
+func (r *idempotencyRepository) Reserve(record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	now := time.Now()
+
+	// Устаревший ключ можно использовать повторно
+	if _, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE key = $1 AND expires_at <= $2`, record.Key, now); err != nil {
+		return nil, fmt.Errorf("failed to delete expired idempotency key: %w", err)
+	}
+
+	record.CreatedAt = now
+	result, err := r.db.Exec(`
+		INSERT INTO idempotency_keys (key, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO NOTHING
+	`, record.Key, record.Fingerprint, record.CreatedAt, record.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil, nil
+	}
+
+	var existing models.IdempotencyRecord
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	err = r.db.QueryRow(`
+		SELECT key, fingerprint, completed, status_code, content_type, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1
+	`, record.Key).Scan(
+		&existing.Key, &existing.Fingerprint, &existing.Completed, &statusCode, &contentType,
+		&existing.Body, &existing.CreatedAt, &existing.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		// Ключ был освобожден между вставкой и чтением, пробуем занять его снова
+		return r.Reserve(record)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	existing.StatusCode = int(statusCode.Int64)
+	existing.ContentType = contentType.String
+
+	return &existing, nil
+}
+
+// Complete сохраняет ответ на запрос с занятым ключом
+func (r *idempotencyRepository) Complete(key string, statusCode int, contentType string, body []byte) error {
+	_, err := r.db.Exec(`
+		UPDATE idempotency_keys
+		SET completed = TRUE, status_code = $1, content_type = $2, response_body = $3
+		WHERE key = $4
+	`, statusCode, contentType, body, key)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release освобождает ключ, чтобы запрос можно было повторить (например, после ошибки сервера)
+func (r *idempotencyRepository) Release(key string) error {
+	if _, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired удаляет ключи, срок хранения которых истек до before
+func (r *idempotencyRepository) DeleteExpired(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
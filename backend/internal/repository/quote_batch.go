@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"quotes-backend/internal/dedup"
+	"quotes-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// QuoteWriter записывает цитаты в рамках открытой транзакции
+// Используется для пакетного импорта, где несколько вставок должны быть атомарными
+type QuoteWriter interface {
+	Insert(quote *models.Quote, editor string) error
+	FindExisting(quotes []*models.Quote, accepted []string) (map[string]bool, error)
+}
+
+// queryer выполняет запросы как вне транзакции (*sql.DB), так и внутри нее (*sql.Tx)
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type txQuoteWriter struct {
+	tx                  *sql.Tx
+	similarityThreshold float64
+}
+
+// Insert создает цитату и ее первую ревизию внутри транзакции
+func (w *txQuoteWriter) Insert(quote *models.Quote, editor string) error {
+	query := `
+		INSERT INTO quotes (
+			id, text, author, likes_count,
+			source_title, source_year, source_page, source_url, source_context,
+			attribution_status, attribution_note, content_hash, status, publish_at,
+			created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+
+	now := time.Now()
+	quote.CreatedAt = now
+	quote.UpdatedAt = now
+	quote.LikesCount = 0
+	quote.Version = 1
+	if quote.AttributionStatus == "" {
+		quote.AttributionStatus = models.AttributionUnknown
+	}
+	if quote.Status == "" {
+		quote.Status = models.StatusApproved
+	}
+
+	args := []interface{}{quote.ID, quote.Text, quote.Author, quote.LikesCount}
+	args = append(args, sourceArgs(quote.Source)...)
+	args = append(args, quote.AttributionStatus, nullString(quote.AttributionNote), dedup.ContentHash(quote.Text), quote.Status, nullTime(quote.PublishAt), quote.CreatedAt, quote.UpdatedAt)
+
+	if _, err := w.tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to create quote: %w", err)
+	}
+
+	return insertRevision(w.tx, quote.ID, nil, quote.Snapshot(), editor, now)
+}
+
+// InTransaction выполняет fn в транзакции
+// Транзакция фиксируется, только если fn завершилась без ошибки
+func (r *quoteRepository) InTransaction(fn func(w QuoteWriter) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			// Игнорируем ошибку, если транзакция уже была закоммичена
+		}
+	}()
+
+	if err := fn(&txQuoteWriter{tx: tx, similarityThreshold: r.similarityThreshold}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// QuoteKey возвращает ключ цитаты для поиска дубликатов
+// Цитаты с одинаковым нормализованным текстом (см. dedup.Normalize) имеют одинаковый ключ
+func QuoteKey(text string) string {
+	return dedup.ContentHash(text)
+}
+
+// FindExisting возвращает ключи (см. QuoteKey) цитат, у которых уже есть дубликат:
+// цитата в базе данных с тем же нормализованным текстом или с похожим текстом (триграммная похожесть не ниже порога),
+// похожая цитата раньше в том же списке или похожий текст из accepted (записи, уже принятые импортом, но не сохраненные)
+func (r *quoteRepository) FindExisting(quotes []*models.Quote, accepted []string) (map[string]bool, error) {
+	return findExisting(r.db, r.similarityThreshold, quotes, accepted)
+}
+
+// FindExisting ищет дубликаты как quoteRepository.FindExisting, но внутри транзакции,
+// поэтому видит цитаты, созданные в ней ранее
+func (w *txQuoteWriter) FindExisting(quotes []*models.Quote, accepted []string) (map[string]bool, error) {
+	return findExisting(w.tx, w.similarityThreshold, quotes, accepted)
+}
+
+func findExisting(db queryer, similarityThreshold float64, quotes []*models.Quote, accepted []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(quotes) == 0 {
+		return existing, nil
+	}
+
+	texts := make([]string, len(quotes))
+	hashes := make([]string, len(quotes))
+	for i, quote := range quotes {
+		texts[i] = quote.Text
+		hashes[i] = QuoteKey(quote.Text)
+	}
+
+	query := `
+		SELECT candidate.hash
+		FROM unnest($1::text[], $2::text[]) WITH ORDINALITY AS candidate(text, hash, position)
+		WHERE EXISTS (
+			SELECT 1 FROM quotes
+			WHERE ` + activeCondition + `
+				AND (content_hash = candidate.hash
+					OR (lower(text) % lower(candidate.text) AND similarity(lower(text), lower(candidate.text)) >= $3))
+		)
+		OR EXISTS (
+			SELECT 1 FROM unnest($1::text[]) WITH ORDINALITY AS earlier(text, position)
+			WHERE earlier.position < candidate.position
+				AND similarity(lower(earlier.text), lower(candidate.text)) >= $3
+		)
+		OR EXISTS (
+			SELECT 1 FROM unnest($4::text[]) AS prior(text)
+			WHERE similarity(lower(prior.text), lower(candidate.text)) >= $3
+		)`
+
+	rows, err := db.Query(query, pq.Array(texts), pq.Array(hashes), similarityThreshold, pq.Array(accepted))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing quotes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("failed to scan existing quote: %w", err)
+		}
+		existing[hash] = true
+	}
+
+	return existing, rows.Err()
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"quotes-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// dailyDateLayout - формат дат цитат дня в запросах
+const dailyDateLayout = "2006-01-02"
+
+// GetDailyQuotes возвращает цитаты дня за даты в интервале [from, to] (включительно)
+// Датам без цитаты закрепляются опубликованные цитаты, которые дольше всех не были цитатой дня;
+// цитаты, снятые с публикации, заменяются. Обычно все даты уже закреплены и запрос только читает их;
+// закрепление выполняется в одной транзакции с ON CONFLICT, поэтому параллельные запросы получают одни и те же цитаты
+func (r *quoteRepository) GetDailyQuotes(from, to time.Time) ([]models.DailyQuote, error) {
+	daily, missing, err := r.readDailyQuotes(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) == 0 {
+		return daily, nil
+	}
+
+	if err := r.assignDailyQuotes(missing); err != nil {
+		return nil, err
+	}
+
+	daily, _, err = r.readDailyQuotes(from, to)
+	return daily, err
+}
+
+// readDailyQuotes возвращает опубликованные цитаты дня за интервал и даты, которым нужно закрепить цитату:
+// даты без цитаты и даты, цитата которых снята с публикации
+func (r *quoteRepository) readDailyQuotes(from, to time.Time) ([]models.DailyQuote, []string, error) {
+	query := `
+		SELECT ` + quoteColumns + `, dq.day, dq.assigned_at, (` + visibleCondition + `) AS visible
+		FROM daily_quotes dq
+		JOIN quotes ON quotes.id = dq.quote_id
+		WHERE dq.day BETWEEN $1 AND $2
+		ORDER BY dq.day`
+
+	rows, err := r.db.Query(query, from.Format(dailyDateLayout), to.Format(dailyDateLayout))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get daily quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var daily []models.DailyQuote
+	assigned := make(map[string]bool)
+	for rows.Next() {
+		var d models.DailyQuote
+		var visible bool
+		if err := scanQuote(rows, &d.Quote, &d.Date, &d.AssignedAt, &visible); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan daily quote: %w", err)
+		}
+		if visible {
+			daily = append(daily, d)
+			assigned[d.Date.Format(dailyDateLayout)] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to get daily quotes: %w", err)
+	}
+
+	var missing []string
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if date := day.Format(dailyDateLayout); !assigned[date] {
+			missing = append(missing, date)
+		}
+	}
+
+	return daily, missing, nil
+}
+
+// assignDailyQuotes закрепляет цитаты за датами days в одной транзакции
+// Цитаты выбираются одним запросом: сначала никогда не показанные, затем показанные давнее всего;
+// если опубликованных цитат меньше, чем дат, они повторяются по кругу
+func (r *quoteRepository) assignDailyQuotes(days []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			// Игнорируем ошибку, если транзакция уже была закоммичена
+		}
+	}()
+
+	_, err = tx.Exec(`
+		DELETE FROM daily_quotes
+		WHERE day = ANY($1::date[])
+			AND quote_id NOT IN (SELECT id FROM quotes WHERE `+visibleCondition+`)`, pq.Array(days))
+	if err != nil {
+		return fmt.Errorf("failed to release daily quotes: %w", err)
+	}
+
+	rows, err := tx.Query(`
+		SELECT q.id
+		FROM quotes q
+		LEFT JOIN (SELECT quote_id, MAX(day) AS last_day FROM daily_quotes GROUP BY quote_id) shown ON shown.quote_id = q.id
+		WHERE `+visibleCondition+`
+		ORDER BY shown.last_day NULLS FIRST, random()
+		LIMIT $1`, len(days))
+	if err != nil {
+		return fmt.Errorf("failed to choose daily quotes: %w", err)
+	}
+	var candidates []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan daily quote: %w", err)
+		}
+		candidates = append(candidates, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to choose daily quotes: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(days))
+	for i := range days {
+		ids[i] = candidates[i%len(candidates)]
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO daily_quotes (day, quote_id)
+		SELECT * FROM unnest($1::date[], $2::varchar[])
+		ON CONFLICT (day) DO NOTHING`, pq.Array(days), pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to assign daily quotes: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
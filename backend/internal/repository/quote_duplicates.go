@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"quotes-backend/internal/dedup"
+	"quotes-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// maxDuplicateMatches ограничивает количество дубликатов, возвращаемых для одного текста
+const maxDuplicateMatches = 5
+
+// FindDuplicates возвращает существующие цитаты (в том числе ожидающие модерации) с тем же
+// нормализованным текстом или похожим текстом, начиная с самых похожих
+func (r *quoteRepository) FindDuplicates(text string) ([]models.DuplicateQuote, error) {
+	// Оператор % использует триграммный индекс, точный порог проверяется функцией similarity
+	query := `
+		SELECT ` + quoteColumns + `, similarity(lower(text), lower($1)) AS score, content_hash = $2 AS exact,
+			(` + visibleCondition + `) AS visible
+		FROM quotes
+		WHERE ` + activeCondition + `
+			AND (content_hash = $2 OR (lower(text) % lower($1) AND similarity(lower(text), lower($1)) >= $3))
+		ORDER BY exact DESC, score DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(query, text, dedup.ContentHash(text), r.similarityThreshold, maxDuplicateMatches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	var duplicates []models.DuplicateQuote
+	for rows.Next() {
+		duplicate, err := scanDuplicate(rows)
+		if err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	return duplicates, rows.Err()
+}
+
+// maxClusterSize ограничивает количество дубликатов в одной группе отчета
+// Вместе с размером страницы оно ограничивает число пар, которые читаются за один запрос
+const maxClusterSize = 20
+
+// duplicatePairCondition - условие совпадения цитат a и b: одинаковый нормализованный текст
+// или похожий текст (оператор % использует триграммный индекс, порог $1 проверяется функцией similarity)
+const duplicatePairCondition = `(a.content_hash = b.content_hash
+	OR (lower(a.text) % lower(b.text) AND similarity(lower(a.text), lower(b.text)) >= $1))`
+
+// activePairCondition - activeCondition для обеих цитат пары
+const activePairCondition = `a.deleted_at IS NULL AND a.status <> 'rejected'
+	AND b.deleted_at IS NULL AND b.status <> 'rejected'`
+
+// GetDuplicateClusters возвращает страницу групп существующих цитат, являющихся дубликатами друг друга,
+// и общее количество групп
+// Группа строится вокруг цитаты-якоря и содержит ее дубликаты с большим ID (не более maxClusterSize),
+// поэтому каждая пара дубликатов попадает ровно в одну группу; группы отсортированы по ID якоря
+// Страница якорей выбирается в SQL, поэтому запрос не сравнивает все цитаты попарно
+func (r *quoteRepository) GetDuplicateClusters(page, pageSize int) ([][]models.DuplicateQuote, int, error) {
+	var total int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM quotes a
+		WHERE a.deleted_at IS NULL AND a.status <> 'rejected'
+			AND EXISTS (
+				SELECT 1 FROM quotes b
+				WHERE b.id > a.id AND ` + activePairCondition + ` AND ` + duplicatePairCondition + `
+			)`
+	if err := r.db.QueryRow(countQuery, r.similarityThreshold).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count duplicate clusters: %w", err)
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	query := `
+		WITH anchors AS (
+			SELECT a.id
+			FROM quotes a
+			WHERE a.deleted_at IS NULL AND a.status <> 'rejected'
+				AND EXISTS (
+					SELECT 1 FROM quotes b
+					WHERE b.id > a.id AND ` + activePairCondition + ` AND ` + duplicatePairCondition + `
+				)
+			ORDER BY a.id
+			LIMIT $2 OFFSET $3
+		)
+		SELECT anchors.id, m.id
+		FROM anchors
+		CROSS JOIN LATERAL (
+			SELECT b.id
+			FROM quotes a, quotes b
+			WHERE a.id = anchors.id AND b.id > a.id AND ` + activePairCondition + ` AND ` + duplicatePairCondition + `
+			ORDER BY b.id
+			LIMIT $4
+		) m`
+
+	rows, err := r.db.Query(query, r.similarityThreshold, pageSize, (page-1)*pageSize, maxClusterSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find duplicate pairs: %w", err)
+	}
+	defer rows.Close()
+
+	// Якоря в порядке страницы и участники их групп
+	var anchors []string
+	members := make(map[string][]string)
+	ids := []string{}
+	for rows.Next() {
+		var anchor, id string
+		if err := rows.Scan(&anchor, &id); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan duplicate pair: %w", err)
+		}
+		if _, ok := members[anchor]; !ok {
+			anchors = append(anchors, anchor)
+			ids = append(ids, anchor)
+		}
+		members[anchor] = append(members[anchor], id)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to find duplicate pairs: %w", err)
+	}
+	if len(anchors) == 0 {
+		return nil, total, nil
+	}
+
+	quoteRows, err := r.db.Query(`SELECT `+quoteColumns+`, content_hash FROM quotes WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get duplicate quotes: %w", err)
+	}
+	defer quoteRows.Close()
+
+	type clusterQuote struct {
+		quote models.Quote
+		hash  sql.NullString
+	}
+	quotes := make(map[string]clusterQuote)
+	for quoteRows.Next() {
+		var item clusterQuote
+		if err := scanQuote(quoteRows, &item.quote, &item.hash); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan duplicate quote: %w", err)
+		}
+		quotes[item.quote.ID] = item
+	}
+	if err := quoteRows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to get duplicate quotes: %w", err)
+	}
+
+	clusters := make([][]models.DuplicateQuote, 0, len(anchors))
+	for _, anchor := range anchors {
+		group := make([]clusterQuote, 0, len(members[anchor])+1)
+		for _, id := range append([]string{anchor}, members[anchor]...) {
+			if item, ok := quotes[id]; ok {
+				group = append(group, item)
+			}
+		}
+		if len(group) < 2 {
+			continue
+		}
+
+		// Первой в группе идет самая популярная цитата - предлагаемая цель объединения
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].quote.LikesCount != group[j].quote.LikesCount {
+				return group[i].quote.LikesCount > group[j].quote.LikesCount
+			}
+			return group[i].quote.CreatedAt.Before(group[j].quote.CreatedAt)
+		})
+
+		cluster := make([]models.DuplicateQuote, len(group))
+		for i, item := range group {
+			cluster[i] = models.DuplicateQuote{
+				Quote: item.quote,
+				Exact: item.hash.Valid && item.hash.String == group[0].hash.String,
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, total, nil
+}
+
+// Merge объединяет дубликаты: цитаты sourceIDs перемещаются в корзину,
+// их лайки переносятся на цитату targetID (опубликованную, запланированную, скрытую или черновик)
+// Повторные лайки одного пользователя не переносятся, счетчик учитывает только перенесенные
+func (r *quoteRepository) Merge(targetID string, sourceIDs []string) error {
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return fmt.Errorf("target quote cannot be merged into itself")
+		}
+	}
+	sourceIDs = uniqueIDs(targetID, sourceIDs)[1:]
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			// Игнорируем ошибку, если транзакция уже была закоммичена
+		}
+	}()
+
+	// Блокируем все объединяемые цитаты, чтобы лайки не изменились во время переноса
+	var found int
+	var sourceLikes int
+	err = tx.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(likes_count) FILTER (WHERE id <> $1), 0)
+		FROM (SELECT id, likes_count FROM quotes WHERE id = ANY($2) AND `+activeCondition+` FOR UPDATE) locked
+	`, targetID, pq.Array(append([]string{targetID}, sourceIDs...))).Scan(&found, &sourceLikes)
+	if err != nil {
+		return fmt.Errorf("failed to lock quotes: %w", err)
+	}
+	if found != len(sourceIDs)+1 {
+		return fmt.Errorf("quote not found")
+	}
+
+	// Целью может быть только цитата, доступная для редактирования: лайки, перенесенные на цитату,
+	// ожидающую модерации, пропали бы при ее отклонении
+	var editable bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM quotes WHERE id = $1 AND `+editableCondition+`)`, targetID).Scan(&editable); err != nil {
+		return fmt.Errorf("failed to check target quote: %w", err)
+	}
+	if !editable {
+		return fmt.Errorf("target quote must be published or a draft")
+	}
+
+	// Удаляем лайки, которые после переноса повторяли бы уже существующие
+	result, err := tx.Exec(`
+		DELETE FROM likes l
+		WHERE l.quote_id = ANY($2)
+			AND (
+				EXISTS (SELECT 1 FROM likes t WHERE t.quote_id = $1 AND t.user_ip = l.user_ip)
+				OR EXISTS (
+					SELECT 1 FROM likes o
+					WHERE o.quote_id = ANY($2) AND o.user_ip = l.user_ip
+						AND (o.created_at, o.id) < (l.created_at, l.id)
+				)
+			)
+	`, targetID, pq.Array(sourceIDs))
+	if err != nil {
+		return fmt.Errorf("failed to remove repeated likes: %w", err)
+	}
+	dropped, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE likes SET quote_id = $1 WHERE quote_id = ANY($2)`, targetID, pq.Array(sourceIDs)); err != nil {
+		return fmt.Errorf("failed to move likes: %w", err)
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec(
+		`UPDATE quotes SET likes_count = GREATEST(likes_count + $1, 0), updated_at = $2 WHERE id = $3`,
+		int64(sourceLikes)-dropped, now, targetID,
+	); err != nil {
+		return fmt.Errorf("failed to update likes count: %w", err)
+	}
+
+	// Лайки перенесены, поэтому у объединенных цитат счетчик обнуляется перед перемещением в корзину
+	if _, err := tx.Exec(
+		`UPDATE quotes SET likes_count = 0, deleted_at = $1, version = version + 1 WHERE id = ANY($2)`,
+		now, pq.Array(sourceIDs),
+	); err != nil {
+		return fmt.Errorf("failed to move merged quotes to trash: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// BackfillContentHashes вычисляет content_hash для цитат, у которых он не заполнен
+// Возвращает количество обновленных цитат
+func (r *quoteRepository) BackfillContentHashes() (int, error) {
+	const batchSize = 500
+	total := 0
+
+	for {
+		rows, err := r.db.Query(`SELECT id, text FROM quotes WHERE content_hash IS NULL LIMIT $1`, batchSize)
+		if err != nil {
+			return total, fmt.Errorf("failed to get quotes without content hash: %w", err)
+		}
+
+		var ids, hashes []string
+		for rows.Next() {
+			var id, text string
+			if err := rows.Scan(&id, &text); err != nil {
+				rows.Close()
+				return total, fmt.Errorf("failed to scan quote: %w", err)
+			}
+			ids = append(ids, id)
+			hashes = append(hashes, dedup.ContentHash(text))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return total, fmt.Errorf("failed to get quotes without content hash: %w", err)
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		query := `
+			UPDATE quotes q SET content_hash = h.hash
+			FROM unnest($1::text[], $2::text[]) AS h(id, hash)
+			WHERE q.id = h.id
+		`
+		if _, err := r.db.Exec(query, pq.Array(ids), pq.Array(hashes)); err != nil {
+			return total, fmt.Errorf("failed to update content hashes: %w", err)
+		}
+		total += len(ids)
+	}
+}
+
+// scanDuplicate считывает цитату с оценкой похожести (колонки quoteColumns, score, exact, visible)
+func scanDuplicate(row rowScanner) (models.DuplicateQuote, error) {
+	var duplicate models.DuplicateQuote
+	if err := scanQuote(row, &duplicate.Quote, &duplicate.Similarity, &duplicate.Exact, &duplicate.Visible); err != nil {
+		return duplicate, fmt.Errorf("failed to scan duplicate: %w", err)
+	}
+	return duplicate, nil
+}
+
+// uniqueIDs возвращает список различных идентификаторов
+func uniqueIDs(targetID string, sourceIDs []string) []string {
+	seen := map[string]bool{targetID: true}
+	ids := []string{targetID}
+	for _, id := range sourceIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+
+	"quotes-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// GetStats возвращает статистику цитат с указанными ID
+func (r *quoteRepository) GetStats(ids []string) (map[string]models.QuoteStats, error) {
+	stats := make(map[string]models.QuoteStats, len(ids))
+	if len(ids) == 0 {
+		return stats, nil
+	}
+
+	query := `
+		SELECT q.id, q.likes_count,
+			(SELECT COUNT(*) FROM likes l WHERE l.quote_id = q.id AND l.created_at >= NOW() - INTERVAL '7 days'),
+			(SELECT COUNT(*) FROM quote_revisions qr WHERE qr.quote_id = q.id)
+		FROM quotes q
+		WHERE q.id = ANY($1)`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var s models.QuoteStats
+		if err := rows.Scan(&id, &s.LikesCount, &s.LikesWeek, &s.RevisionsCount); err != nil {
+			return nil, fmt.Errorf("failed to scan quote stats: %w", err)
+		}
+		stats[id] = s
+	}
+
+	return stats, rows.Err()
+}
+
+// GetAuthorDetails возвращает сведения об авторах: псевдонимы и количество опубликованных цитат
+func (r *quoteRepository) GetAuthorDetails(authors []string) (map[string]models.AuthorDetails, error) {
+	details := make(map[string]models.AuthorDetails, len(authors))
+	if len(authors) == 0 {
+		return details, nil
+	}
+
+	query := `
+		SELECT a.author,
+			COALESCE((SELECT array_agg(alias ORDER BY alias) FROM author_aliases WHERE author = a.author), '{}'),
+			(SELECT COUNT(*) FROM quotes WHERE author = a.author AND ` + visibleCondition + `)
+		FROM unnest($1::text[]) AS a(author)`
+
+	rows, err := r.db.Query(query, pq.Array(authors))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author details: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.AuthorDetails
+		if err := rows.Scan(&d.Name, pq.Array(&d.Aliases), &d.QuotesCount); err != nil {
+			return nil, fmt.Errorf("failed to scan author details: %w", err)
+		}
+		details[d.Name] = d
+	}
+
+	return details, rows.Err()
+}
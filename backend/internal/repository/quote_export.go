@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"quotes-backend/internal/models"
+)
+
+// exportFetchSize - количество строк, получаемых из курсора за один запрос
+const exportFetchSize = 500
+
+// Export последовательно передает в fn все цитаты, подходящие под фильтр
+// Используется серверный курсор PostgreSQL, поэтому таблица не загружается в память целиком
+// Если fn возвращает ошибку, выгрузка прекращается
+func (r *quoteRepository) Export(filter models.QuoteFilter, fn func(quote *models.Quote) error) error {
+	where, args := buildQuoteFilter(filter)
+	return r.export(where, args, fn)
+}
+
+// ExportAll последовательно передает в fn все цитаты, кроме находящихся в корзине:
+// в том числе черновики, запланированные, скрытые, ожидающие модерации и отклоненные
+func (r *quoteRepository) ExportAll(fn func(quote *models.Quote) error) error {
+	return r.export(" WHERE "+existingCondition, nil, fn)
+}
+
+// export передает в fn цитаты, отобранные условием where, читая их серверным курсором
+func (r *quoteRepository) export(where string, args []interface{}, fn func(quote *models.Quote) error) error {
+	// Курсоры PostgreSQL существуют только внутри транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			// Игнорируем ошибку, если транзакция уже была закоммичена
+		}
+	}()
+
+	declare := `DECLARE quotes_export NO SCROLL CURSOR FOR
+		SELECT ` + quoteColumns + `
+		FROM quotes` + where + `
+		ORDER BY created_at DESC, id`
+	if _, err := tx.Exec(declare, args...); err != nil {
+		return fmt.Errorf("failed to declare export cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM quotes_export", exportFetchSize)
+	for {
+		rows, err := tx.Query(fetch)
+		if err != nil {
+			return fmt.Errorf("failed to fetch quotes: %w", err)
+		}
+
+		fetched := 0
+		for rows.Next() {
+			var quote models.Quote
+			if err := scanQuote(rows, &quote); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan quote: %w", err)
+			}
+			fetched++
+
+			if err := fn(&quote); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to fetch quotes: %w", err)
+		}
+		rows.Close()
+
+		if fetched < exportFetchSize {
+			break
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
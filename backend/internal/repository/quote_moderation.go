@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"quotes-backend/internal/models"
+)
+
+// GetModerationQueue возвращает цитаты с указанным статусом модерации с пагинацией
+// Ожидающие модерации цитаты возвращаются начиная с самых старых, остальные - с последних решений
+func (r *quoteRepository) GetModerationQueue(page, pageSize int, status string) ([]models.Quote, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM quotes WHERE deleted_at IS NULL AND status = $1`, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count quotes: %w", err)
+	}
+
+	order := "created_at ASC"
+	if status != models.StatusPending {
+		order = "moderated_at DESC NULLS LAST, created_at DESC"
+	}
+
+	query := `
+		SELECT ` + quoteColumns + `
+		FROM quotes
+		WHERE deleted_at IS NULL AND status = $1
+		ORDER BY ` + order + `
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, status, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get moderation queue: %w", err)
+	}
+	defer rows.Close()
+
+	var quotes []models.Quote
+	for rows.Next() {
+		var quote models.Quote
+		if err := scanQuote(rows, &quote); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, total, nil
+}
+
+// GetPending возвращает цитату, ожидающую модерации
+func (r *quoteRepository) GetPending(id string) (*models.Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE id = $1 AND ` + pendingCondition
+
+	var quote models.Quote
+	err := scanQuote(r.db.QueryRow(query, id), &quote)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("quote not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+// Moderate сохраняет решение модератора по цитате, ожидающей модерации
+// quote содержит новый статус (и причину отклонения) вместе с возможными правками полей;
+// правки записываются в историю ревизий
+func (r *quoteRepository) Moderate(id string, quote *models.Quote, editor string) error {
+	now := time.Now()
+	quote.ModeratedAt = &now
+	return r.update(id, pendingCondition, quote, editor)
+}
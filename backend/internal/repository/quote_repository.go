@@ -2,10 +2,12 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
 	"time"
 
+	"quotes-backend/internal/dedup"
 	"quotes-backend/internal/models"
 
 	"github.com/google/uuid"
@@ -15,46 +17,73 @@ import (
 // QuoteRepository определяет интерфейс для работы с цитатами
 type QuoteRepository interface {
 	GetRandom() (*models.Quote, error)
-	GetAll(page, pageSize int, search string) ([]models.Quote, int, error)
+	GetAll(page, pageSize int, filter models.QuoteFilter) ([]models.Quote, int, error)
 	GetByID(id string) (*models.Quote, error)
-	Create(quote *models.Quote) error
-	Update(id string, quote *models.Quote) error
-	Delete(id string) error
+	GetByIDs(ids []string) ([]models.Quote, error)
+	GetStats(ids []string) (map[string]models.QuoteStats, error)
+	GetAuthorDetails(authors []string) (map[string]models.AuthorDetails, error)
+	Create(quote *models.Quote, editor string) error
+	Update(id string, quote *models.Quote, editor string) error
+	Delete(id string, expectedVersion int) error
 	Like(id string, userIP, userAgent string) error
 	IsLiked(id string, userIP string) (bool, error)
 	AreLiked(ids []string, userIP string) (map[string]bool, error) // Batch проверка лайков
 	GetTopWeekly() (*models.Quote, error)
+	GetTopWeeklyList(limit int) ([]models.Quote, error)
+	GetLatestList(limit int, filter models.QuoteFilter) ([]models.Quote, error)
 	GetTopAllTime() (*models.Quote, error)
 	ResetLikes() error
+	GetRevisions(quoteID string) ([]models.QuoteRevision, error)
+	GetRevision(quoteID, revisionID string) (*models.QuoteRevision, error)
+	GetTrash(page, pageSize int) ([]models.Quote, int, error)
+	Restore(id string) error
+	Purge(id string) error
+	PurgeDeletedBefore(before time.Time) (int64, error)
+	InTransaction(fn func(w QuoteWriter) error) error
+	FindExisting(quotes []*models.Quote, accepted []string) (map[string]bool, error)
+	FindDuplicates(text string) ([]models.DuplicateQuote, error)
+	GetDuplicateClusters(page, pageSize int) ([][]models.DuplicateQuote, int, error)
+	Merge(targetID string, sourceIDs []string) error
+	BackfillContentHashes() (int, error)
+	GetEditable(id string) (*models.Quote, error)
+	GetScheduled(from, to time.Time) ([]models.Quote, error)
+	GetDailyQuotes(from, to time.Time) ([]models.DailyQuote, error)
+	GetModerationQueue(page, pageSize int, status string) ([]models.Quote, int, error)
+	GetPending(id string) (*models.Quote, error)
+	Moderate(id string, quote *models.Quote, editor string) error
+	Export(filter models.QuoteFilter, fn func(quote *models.Quote) error) error
+	ExportAll(fn func(quote *models.Quote) error) error
+	GetSitemapPages(pageSize int) ([]models.SitemapPage, error)
+	GetSitemapEntries(fromID string, limit int) ([]models.SitemapEntry, error)
+	UpdateAny(id string, quote *models.Quote, editor string) error
 }
 
+// ErrQuoteModified возвращается при условной записи, если цитата была изменена после чтения
+var ErrQuoteModified = errors.New("quote was modified")
+
 type quoteRepository struct {
 	db *sql.DB
+	// similarityThreshold - минимальная триграммная похожесть текста (0..1), при которой цитаты считаются дубликатами
+	similarityThreshold float64
 }
 
 // NewQuoteRepository создает новый экземпляр репозитория
-func NewQuoteRepository(db *sql.DB) QuoteRepository {
-	return &quoteRepository{db: db}
+func NewQuoteRepository(db *sql.DB, similarityThreshold float64) QuoteRepository {
+	return &quoteRepository{db: db, similarityThreshold: similarityThreshold}
 }
 
 // GetRandom возвращает случайную цитату
 func (r *quoteRepository) GetRandom() (*models.Quote, error) {
 	query := `
-		SELECT id, text, author, likes_count, created_at, updated_at 
+		SELECT ` + quoteColumns + `
 		FROM quotes 
+		WHERE ` + visibleCondition + `
 		ORDER BY RANDOM() 
 		LIMIT 1
 	`
 
 	var quote models.Quote
-	err := r.db.QueryRow(query).Scan(
-		&quote.ID,
-		&quote.Text,
-		&quote.Author,
-		&quote.LikesCount,
-		&quote.CreatedAt,
-		&quote.UpdatedAt,
-	)
+	err := scanQuote(r.db.QueryRow(query), &quote)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("no quotes found")
@@ -66,17 +95,13 @@ func (r *quoteRepository) GetRandom() (*models.Quote, error) {
 	return &quote, nil
 }
 
-// GetAll возвращает все цитаты с пагинацией и поиском
-func (r *quoteRepository) GetAll(page, pageSize int, search string) ([]models.Quote, int, error) {
+// GetAll возвращает все цитаты с пагинацией и фильтрацией
+func (r *quoteRepository) GetAll(page, pageSize int, filter models.QuoteFilter) ([]models.Quote, int, error) {
+	where, args := buildQuoteFilter(filter)
+
 	// Подсчет общего количества
 	var total int
-	countQuery := "SELECT COUNT(*) FROM quotes"
-	args := []interface{}{}
-
-	if search != "" {
-		countQuery += " WHERE text ILIKE $1 OR author ILIKE $1"
-		args = append(args, "%"+search+"%")
-	}
+	countQuery := "SELECT COUNT(*) FROM quotes" + where
 
 	err := r.db.QueryRow(countQuery, args...).Scan(&total)
 	if err != nil {
@@ -86,18 +111,11 @@ func (r *quoteRepository) GetAll(page, pageSize int, search string) ([]models.Qu
 	// Получение цитат с пагинацией
 	offset := (page - 1) * pageSize
 	query := `
-		SELECT id, text, author, likes_count, created_at, updated_at 
+		SELECT ` + quoteColumns + `
 		FROM quotes
-	`
-
-	if search != "" {
-		query += " WHERE text ILIKE $1 OR author ILIKE $1"
-		query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
-		args = append(args, pageSize, offset)
-	} else {
-		query += " ORDER BY created_at DESC LIMIT $1 OFFSET $2"
-		args = []interface{}{pageSize, offset}
-	}
+	` + where
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, pageSize, offset)
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
@@ -108,14 +126,7 @@ func (r *quoteRepository) GetAll(page, pageSize int, search string) ([]models.Qu
 	var quotes []models.Quote
 	for rows.Next() {
 		var quote models.Quote
-		if err := rows.Scan(
-			&quote.ID,
-			&quote.Text,
-			&quote.Author,
-			&quote.LikesCount,
-			&quote.CreatedAt,
-			&quote.UpdatedAt,
-		); err != nil {
+		if err := scanQuote(rows, &quote); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan quote: %w", err)
 		}
 		quotes = append(quotes, quote)
@@ -127,20 +138,13 @@ func (r *quoteRepository) GetAll(page, pageSize int, search string) ([]models.Qu
 // GetByID возвращает цитату по ID
 func (r *quoteRepository) GetByID(id string) (*models.Quote, error) {
 	query := `
-		SELECT id, text, author, likes_count, created_at, updated_at 
+		SELECT ` + quoteColumns + `
 		FROM quotes 
-		WHERE id = $1
+		WHERE id = $1 AND ` + visibleCondition + `
 	`
 
 	var quote models.Quote
-	err := r.db.QueryRow(query, id).Scan(
-		&quote.ID,
-		&quote.Text,
-		&quote.Author,
-		&quote.LikesCount,
-		&quote.CreatedAt,
-		&quote.UpdatedAt,
-	)
+	err := scanQuote(r.db.QueryRow(query, id), &quote)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("quote not found")
@@ -152,67 +156,142 @@ func (r *quoteRepository) GetByID(id string) (*models.Quote, error) {
 	return &quote, nil
 }
 
-// Create создает новую цитату
-func (r *quoteRepository) Create(quote *models.Quote) error {
-	query := `
-		INSERT INTO quotes (id, text, author, likes_count, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`
+// GetByIDs возвращает опубликованные цитаты с указанными ID
+// Порядок результата не определен; отсутствующие и скрытые цитаты пропускаются
+func (r *quoteRepository) GetByIDs(ids []string) ([]models.Quote, error) {
+	if len(ids) == 0 {
+		return []models.Quote{}, nil
+	}
 
-	now := time.Now()
-	quote.CreatedAt = now
-	quote.UpdatedAt = now
-	quote.LikesCount = 0
-
-	_, err := r.db.Exec(
-		query,
-		quote.ID,
-		quote.Text,
-		quote.Author,
-		quote.LikesCount,
-		quote.CreatedAt,
-		quote.UpdatedAt,
-	)
+	query := `
+		SELECT ` + quoteColumns + `
+		FROM quotes
+		WHERE id = ANY($1) AND ` + visibleCondition
 
+	rows, err := r.db.Query(query, pq.Array(ids))
 	if err != nil {
-		return fmt.Errorf("failed to create quote: %w", err)
+		return nil, fmt.Errorf("failed to get quotes: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	quotes := make([]models.Quote, 0, len(ids))
+	for rows.Next() {
+		var quote models.Quote
+		if err := scanQuote(rows, &quote); err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, rows.Err()
+}
+
+// Create создает новую цитату и записывает ее первую ревизию
+func (r *quoteRepository) Create(quote *models.Quote, editor string) error {
+	return r.InTransaction(func(w QuoteWriter) error {
+		return w.Insert(quote, editor)
+	})
 }
 
 // Update обновляет существующую цитату
-func (r *quoteRepository) Update(id string, quote *models.Quote) error {
+// Предыдущие и новые значения полей сохраняются в таблицу quote_revisions
+// в той же транзакции, что и само обновление
+// Запись условная: если версия цитаты в базе отличается от quote.Version (цитата изменена после чтения),
+// возвращается ErrQuoteModified
+// Обновлять можно опубликованные и запланированные цитаты, черновики и цитаты, скрытые по жалобам
+func (r *quoteRepository) Update(id string, quote *models.Quote, editor string) error {
+	return r.update(id, editableCondition, quote, editor)
+}
+
+// UpdateAny обновляет цитату в любом статусе, кроме находящейся в корзине, и записывает ревизию
+// Используется для массовых исправлений полей: статус и решение модератора сохраняются из quote
+func (r *quoteRepository) UpdateAny(id string, quote *models.Quote, editor string) error {
+	return r.update(id, existingCondition, quote, editor)
+}
+
+// update обновляет цитату, удовлетворяющую условию condition, и записывает ревизию
+func (r *quoteRepository) update(id, condition string, quote *models.Quote, editor string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			// Игнорируем ошибку, если транзакция уже была закоммичена
+		}
+	}()
+
+	// Блокируем строку, чтобы ревизия содержала актуальные старые значения
+	var current models.Quote
+	err = scanQuote(tx.QueryRow(`SELECT `+quoteColumns+` FROM quotes WHERE id = $1 AND `+condition+` FOR UPDATE`, id), &current)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("quote not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get quote: %w", err)
+	}
+	if current.Version != quote.Version {
+		return ErrQuoteModified
+	}
+
 	query := `
 		UPDATE quotes 
-		SET text = $1, author = $2, updated_at = $3
-		WHERE id = $4
+		SET text = $1, author = $2,
+			source_title = $3, source_year = $4, source_page = $5, source_url = $6, source_context = $7,
+			attribution_status = $8, attribution_note = $9,
+			content_hash = $10, status = $11, rejection_reason = $12, moderated_at = $13, publish_at = $14,
+			updated_at = $15, version = version + 1
+		WHERE id = $16 AND version = $17
 	`
 
 	quote.UpdatedAt = time.Now()
 
-	result, err := r.db.Exec(query, quote.Text, quote.Author, quote.UpdatedAt, id)
+	args := []interface{}{quote.Text, quote.Author}
+	args = append(args, sourceArgs(quote.Source)...)
+	args = append(args, quote.AttributionStatus, nullString(quote.AttributionNote), dedup.ContentHash(quote.Text))
+	args = append(args, quote.Status, nullString(quote.RejectionReason), quote.ModeratedAt, nullTime(quote.PublishAt), quote.UpdatedAt, id, quote.Version)
+
+	result, err := tx.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update quote: %w", err)
 	}
-
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
-
 	if rowsAffected == 0 {
-		return fmt.Errorf("quote not found")
+		return ErrQuoteModified
+	}
+	quote.Version++
+
+	// Ревизия записывается только если редактируемые поля действительно изменились
+	oldSnapshot := current.Snapshot()
+	revision := models.QuoteRevision{Old: &oldSnapshot, New: quote.Snapshot()}
+	if len(revision.Changes()) > 0 {
+		if err := insertRevision(tx, id, &oldSnapshot, revision.New, editor, quote.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
-// Delete удаляет цитату
-func (r *quoteRepository) Delete(id string) error {
-	query := `DELETE FROM quotes WHERE id = $1`
+// Delete перемещает цитату в корзину (мягкое удаление)
+// Лайки и история изменений сохраняются, цитата перестает отображаться во всех списках
+// Если expectedVersion > 0, цитата удаляется только при совпадении версии, иначе возвращается ErrQuoteModified
+func (r *quoteRepository) Delete(id string, expectedVersion int) error {
+	query := `UPDATE quotes SET deleted_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL`
+	args := []interface{}{time.Now(), id}
+	if expectedVersion > 0 {
+		query += ` AND version = $3`
+		args = append(args, expectedVersion)
+	}
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete quote: %w", err)
 	}
@@ -223,6 +302,15 @@ func (r *quoteRepository) Delete(id string) error {
 	}
 
 	if rowsAffected == 0 {
+		if expectedVersion > 0 {
+			var exists bool
+			if err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM quotes WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+				return fmt.Errorf("failed to check quote: %w", err)
+			}
+			if exists {
+				return ErrQuoteModified
+			}
+		}
 		return fmt.Errorf("quote not found")
 	}
 
@@ -270,7 +358,7 @@ func (r *quoteRepository) Like(id string, userIP, userAgent string) error {
 	updateQuery := `
 		UPDATE quotes 
 		SET likes_count = likes_count + 1, updated_at = $1
-		WHERE id = $2
+		WHERE id = $2 AND ` + visibleCondition + `
 	`
 	result, err := tx.Exec(updateQuery, time.Now(), id)
 	if err != nil {
@@ -352,22 +440,15 @@ func (r *quoteRepository) AreLiked(ids []string, userIP string) (map[string]bool
 // GetTopWeekly возвращает цитату с наибольшим количеством лайков за последнюю неделю
 func (r *quoteRepository) GetTopWeekly() (*models.Quote, error) {
 	query := `
-		SELECT id, text, author, likes_count, created_at, updated_at 
+		SELECT ` + quoteColumns + `
 		FROM quotes 
-		WHERE created_at >= NOW() - INTERVAL '7 days'
+		WHERE created_at >= NOW() - INTERVAL '7 days' AND ` + visibleCondition + `
 		ORDER BY likes_count DESC, created_at DESC
 		LIMIT 1
 	`
 
 	var quote models.Quote
-	err := r.db.QueryRow(query).Scan(
-		&quote.ID,
-		&quote.Text,
-		&quote.Author,
-		&quote.LikesCount,
-		&quote.CreatedAt,
-		&quote.UpdatedAt,
-	)
+	err := scanQuote(r.db.QueryRow(query), &quote)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("no quotes found for the last week")
@@ -379,24 +460,79 @@ func (r *quoteRepository) GetTopWeekly() (*models.Quote, error) {
 	return &quote, nil
 }
 
+// GetTopWeeklyList возвращает до limit цитат с наибольшим количеством лайков за последние 7 дней
+func (r *quoteRepository) GetTopWeeklyList(limit int) ([]models.Quote, error) {
+	query := `
+		SELECT ` + quoteColumns + `
+		FROM quotes
+		WHERE created_at >= NOW() - INTERVAL '7 days' AND ` + visibleCondition + `
+		ORDER BY likes_count DESC, created_at DESC
+		LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top weekly quotes: %w", err)
+	}
+	defer rows.Close()
+
+	quotes := make([]models.Quote, 0, limit)
+	for rows.Next() {
+		var quote models.Quote
+		if err := scanQuote(rows, &quote); err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, rows.Err()
+}
+
+// publishedAtExpr - время, когда цитата стала видна посетителям: самое позднее из времени создания,
+// одобрения модератором и отложенной публикации (GREATEST пропускает NULL); см. models.Quote.PublishedAt
+const publishedAtExpr = "GREATEST(created_at, moderated_at, publish_at)"
+
+// GetLatestList возвращает до limit последних опубликованных цитат с учетом фильтров
+// Цитаты упорядочены по времени публикации, поэтому одобренные и запланированные цитаты
+// попадают в начало списка, когда становятся видны, а не по времени создания
+func (r *quoteRepository) GetLatestList(limit int, filter models.QuoteFilter) ([]models.Quote, error) {
+	where, args := buildQuoteFilter(filter)
+	query := `
+		SELECT ` + quoteColumns + `
+		FROM quotes
+	` + where
+	query += fmt.Sprintf(" ORDER BY "+publishedAtExpr+" DESC, id DESC LIMIT $%d", len(args)+1)
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest quotes: %w", err)
+	}
+	defer rows.Close()
+
+	quotes := make([]models.Quote, 0, limit)
+	for rows.Next() {
+		var quote models.Quote
+		if err := scanQuote(rows, &quote); err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, rows.Err()
+}
+
 // GetTopAllTime возвращает цитату с наибольшим количеством лайков за всё время
 func (r *quoteRepository) GetTopAllTime() (*models.Quote, error) {
 	query := `
-		SELECT id, text, author, likes_count, created_at, updated_at 
+		SELECT ` + quoteColumns + `
 		FROM quotes 
+		WHERE ` + visibleCondition + `
 		ORDER BY likes_count DESC, created_at DESC
 		LIMIT 1
 	`
 
 	var quote models.Quote
-	err := r.db.QueryRow(query).Scan(
-		&quote.ID,
-		&quote.Text,
-		&quote.Author,
-		&quote.LikesCount,
-		&quote.CreatedAt,
-		&quote.UpdatedAt,
-	)
+	err := scanQuote(r.db.QueryRow(query), &quote)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("no quotes found")
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"quotes-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// insertRevision сохраняет ревизию цитаты в рамках транзакции
+func insertRevision(tx *sql.Tx, quoteID string, old *models.QuoteSnapshot, new models.QuoteSnapshot, editor string, createdAt time.Time) error {
+	var oldData interface{}
+	if old != nil {
+		data, err := json.Marshal(old)
+		if err != nil {
+			return fmt.Errorf("failed to encode revision: %w", err)
+		}
+		oldData = data
+	}
+
+	newData, err := json.Marshal(new)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision: %w", err)
+	}
+
+	query := `
+		INSERT INTO quote_revisions (id, quote_id, old_data, new_data, editor, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.Exec(query, uuid.New().String(), quoteID, oldData, newData, editor, createdAt); err != nil {
+		return fmt.Errorf("failed to save revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisions возвращает ревизии цитаты, начиная с самой новой
+func (r *quoteRepository) GetRevisions(quoteID string) ([]models.QuoteRevision, error) {
+	query := `
+		SELECT id, quote_id, old_data, new_data, editor, created_at
+		FROM quote_revisions
+		WHERE quote_id = $1
+		ORDER BY created_at DESC, id
+	`
+
+	rows, err := r.db.Query(query, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.QuoteRevision{}
+	for rows.Next() {
+		var revision models.QuoteRevision
+		if err := scanRevision(rows, &revision); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision возвращает ревизию цитаты по ID
+func (r *quoteRepository) GetRevision(quoteID, revisionID string) (*models.QuoteRevision, error) {
+	query := `
+		SELECT id, quote_id, old_data, new_data, editor, created_at
+		FROM quote_revisions
+		WHERE quote_id = $1 AND id = $2
+	`
+
+	var revision models.QuoteRevision
+	err := scanRevision(r.db.QueryRow(query, quoteID, revisionID), &revision)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("revision not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return &revision, nil
+}
+
+// scanRevision считывает ревизию и декодирует JSON снимки
+func scanRevision(row rowScanner, revision *models.QuoteRevision) error {
+	var oldData, newData []byte
+	var editor sql.NullString
+
+	if err := row.Scan(&revision.ID, &revision.QuoteID, &oldData, &newData, &editor, &revision.CreatedAt); err != nil {
+		return err
+	}
+
+	if oldData != nil {
+		revision.Old = &models.QuoteSnapshot{}
+		if err := json.Unmarshal(oldData, revision.Old); err != nil {
+			return fmt.Errorf("failed to decode revision: %w", err)
+		}
+	}
+	if err := json.Unmarshal(newData, &revision.New); err != nil {
+		return fmt.Errorf("failed to decode revision: %w", err)
+	}
+	revision.Editor = editor.String
+
+	return nil
+}
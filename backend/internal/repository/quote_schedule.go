@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"quotes-backend/internal/models"
+)
+
+// GetEditable возвращает цитату, доступную администратору для редактирования,
+// включая черновики, запланированные и скрытые по жалобам цитаты
+func (r *quoteRepository) GetEditable(id string) (*models.Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE id = $1 AND ` + editableCondition
+
+	var quote models.Quote
+	err := scanQuote(r.db.QueryRow(query, id), &quote)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("quote not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+// GetScheduled возвращает цитаты и черновики с временем публикации в интервале [from, to),
+// упорядоченные по времени публикации
+func (r *quoteRepository) GetScheduled(from, to time.Time) ([]models.Quote, error) {
+	query := `
+		SELECT ` + quoteColumns + `
+		FROM quotes
+		WHERE ` + editableCondition + ` AND publish_at >= $1 AND publish_at < $2
+		ORDER BY publish_at, created_at
+	`
+
+	rows, err := r.db.Query(query, from.Local(), to.Local())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var quotes []models.Quote
+	for rows.Next() {
+		var quote models.Quote
+		if err := scanQuote(rows, &quote); err != nil {
+			return nil, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, rows.Err()
+}
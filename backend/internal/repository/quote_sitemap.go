@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"fmt"
+
+	"quotes-backend/internal/models"
+)
+
+// GetSitemapPages делит опубликованные цитаты (в порядке ID) на страницы по pageSize цитат
+// и возвращает первый ID и время последнего изменения каждой страницы
+// Первая страница всегда возвращается с пустым FromID, даже если цитат нет
+func (r *quoteRepository) GetSitemapPages(pageSize int) ([]models.SitemapPage, error) {
+	query := `
+		SELECT MIN(id), MAX(updated_at)
+		FROM (
+			SELECT id, updated_at, (row_number() OVER (ORDER BY id) - 1) / $1 AS page
+			FROM quotes
+			WHERE ` + visibleCondition + `
+		) pages
+		GROUP BY page
+		ORDER BY page`
+
+	rows, err := r.db.Query(query, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sitemap pages: %w", err)
+	}
+	defer rows.Close()
+
+	var pages []models.SitemapPage
+	for rows.Next() {
+		var page models.SitemapPage
+		if err := rows.Scan(&page.FromID, &page.LastModified); err != nil {
+			return nil, fmt.Errorf("failed to scan sitemap page: %w", err)
+		}
+		pages = append(pages, page)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get sitemap pages: %w", err)
+	}
+
+	if len(pages) == 0 {
+		return []models.SitemapPage{{}}, nil
+	}
+	pages[0].FromID = ""
+	return pages, nil
+}
+
+// GetSitemapEntries возвращает до limit опубликованных цитат с ID не меньше fromID в порядке ID
+// Страницы выбираются по ключу (id >= fromID), поэтому запрос читает только нужный участок индекса
+func (r *quoteRepository) GetSitemapEntries(fromID string, limit int) ([]models.SitemapEntry, error) {
+	query := `
+		SELECT id, updated_at
+		FROM quotes
+		WHERE ` + visibleCondition + ` AND id >= $1
+		ORDER BY id
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, fromID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sitemap entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.SitemapEntry
+	for rows.Next() {
+		var entry models.SitemapEntry
+		if err := rows.Scan(&entry.ID, &entry.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan sitemap entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
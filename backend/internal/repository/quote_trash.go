@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"quotes-backend/internal/models"
+)
+
+// GetTrash возвращает цитаты из корзины с пагинацией, начиная с последних удаленных
+func (r *quoteRepository) GetTrash(page, pageSize int) ([]models.Quote, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM quotes WHERE deleted_at IS NOT NULL`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted quotes: %w", err)
+	}
+
+	query := `
+		SELECT ` + quoteColumns + `
+		FROM quotes
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Query(query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get deleted quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var quotes []models.Quote
+	for rows.Next() {
+		var quote models.Quote
+		if err := scanQuote(rows, &quote); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan quote: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, total, nil
+}
+
+// Restore возвращает цитату из корзины вместе с ее лайками
+func (r *quoteRepository) Restore(id string) error {
+	query := `UPDATE quotes SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore quote: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("quote not found in trash")
+	}
+
+	return nil
+}
+
+// Purge окончательно удаляет цитату из корзины
+// Лайки и ревизии удаляются каскадно (ON DELETE CASCADE)
+func (r *quoteRepository) Purge(id string) error {
+	query := `DELETE FROM quotes WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to purge quote: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("quote not found in trash")
+	}
+
+	return nil
+}
+
+// PurgeDeletedBefore окончательно удаляет цитаты, попавшие в корзину раньше указанного времени
+// Возвращает количество удаленных цитат
+func (r *quoteRepository) PurgeDeletedBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM quotes WHERE deleted_at IS NOT NULL AND deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted quotes: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return purged, nil
+}
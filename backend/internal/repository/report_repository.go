@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"quotes-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ReportRepository определяет интерфейс для работы с жалобами на цитаты
+type ReportRepository interface {
+	Create(report *models.QuoteReport, hideThreshold int) (bool, error)
+	GetFlagged(page, pageSize int) ([]models.FlaggedQuote, int, error)
+	GetReports(quoteID string) ([]models.QuoteReport, error)
+	Resolve(quoteID, action, note string) (int64, error)
+}
+
+type reportRepository struct {
+	db *sql.DB
+}
+
+// NewReportRepository создает новый экземпляр репозитория жалоб
+func NewReportRepository(db *sql.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// Create сохраняет жалобу на опубликованную цитату
+// Один клиент может оставить только одну открытую жалобу на цитату
+// Если hideThreshold > 0 и количество открытых жалоб достигло порога, цитата скрывается;
+// возвращаемое значение показывает, что цитата скрыта
+func (r *reportRepository) Create(report *models.QuoteReport, hideThreshold int) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			// Игнорируем ошибку, если транзакция уже была закоммичена
+		}
+	}()
+
+	// Блокируем цитату, чтобы подсчет жалоб и скрытие выполнялись последовательно
+	var id string
+	err = tx.QueryRow(`SELECT id FROM quotes WHERE id = $1 AND `+visibleCondition+` FOR UPDATE`, report.QuoteID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("quote not found")
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	report.CreatedAt = time.Now()
+	result, err := tx.Exec(`
+		INSERT INTO quote_reports (id, quote_id, reason, comment, client_ip, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (quote_id, client_ip) WHERE resolved_at IS NULL DO NOTHING
+	`, report.ID, report.QuoteID, report.Reason, nullString(report.Comment), report.ClientIP, nullString(report.UserAgent), report.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create report: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, fmt.Errorf("quote already reported")
+	}
+
+	hidden := false
+	if hideThreshold > 0 {
+		var open int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM quote_reports WHERE quote_id = $1 AND resolved_at IS NULL`, report.QuoteID).Scan(&open); err != nil {
+			return false, fmt.Errorf("failed to count reports: %w", err)
+		}
+		if open >= hideThreshold {
+			if _, err := tx.Exec(`UPDATE quotes SET hidden_at = $1 WHERE id = $2`, report.CreatedAt, report.QuoteID); err != nil {
+				return false, fmt.Errorf("failed to hide quote: %w", err)
+			}
+			hidden = true
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return hidden, nil
+}
+
+// GetFlagged возвращает цитаты с открытыми жалобами, начиная с цитат с наибольшим количеством жалоб
+// В выборку попадают и цитаты, скрытые по жалобам
+func (r *reportRepository) GetFlagged(page, pageSize int) ([]models.FlaggedQuote, int, error) {
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(DISTINCT r.quote_id)
+		FROM quote_reports r
+		JOIN quotes q ON q.id = r.quote_id
+		WHERE r.resolved_at IS NULL AND q.deleted_at IS NULL
+	`).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count flagged quotes: %w", err)
+	}
+
+	query := `
+		SELECT ` + quoteColumns + `, stats.report_count, stats.last_reported_at, hidden_at
+		FROM quotes
+		JOIN (
+			SELECT quote_id, COUNT(*) AS report_count, MAX(created_at) AS last_reported_at
+			FROM quote_reports
+			WHERE resolved_at IS NULL
+			GROUP BY quote_id
+		) stats ON stats.quote_id = quotes.id
+		WHERE deleted_at IS NULL
+		ORDER BY stats.report_count DESC, stats.last_reported_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Query(query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get flagged quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var flagged []models.FlaggedQuote
+	index := make(map[string]int)
+	for rows.Next() {
+		var item models.FlaggedQuote
+		var hiddenAt sql.NullTime
+		if err := scanQuote(rows, &item.Quote, &item.ReportCount, &item.LastReportedAt, &hiddenAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan flagged quote: %w", err)
+		}
+		if hiddenAt.Valid {
+			item.HiddenAt = &hiddenAt.Time
+		}
+		item.Reasons = make(map[string]int)
+		index[item.Quote.ID] = len(flagged)
+		flagged = append(flagged, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to get flagged quotes: %w", err)
+	}
+	if len(flagged) == 0 {
+		return flagged, total, nil
+	}
+
+	// Разбивка открытых жалоб по причинам для цитат текущей страницы
+	ids := make([]string, len(flagged))
+	for i, item := range flagged {
+		ids[i] = item.Quote.ID
+	}
+
+	reasonRows, err := r.db.Query(`
+		SELECT quote_id, reason, COUNT(*)
+		FROM quote_reports
+		WHERE quote_id = ANY($1) AND resolved_at IS NULL
+		GROUP BY quote_id, reason
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get report reasons: %w", err)
+	}
+	defer reasonRows.Close()
+
+	for reasonRows.Next() {
+		var quoteID, reason string
+		var count int
+		if err := reasonRows.Scan(&quoteID, &reason, &count); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan report reason: %w", err)
+		}
+		flagged[index[quoteID]].Reasons[reason] = count
+	}
+
+	return flagged, total, nil
+}
+
+// GetReports возвращает все жалобы на цитату, начиная с последних
+func (r *reportRepository) GetReports(quoteID string) ([]models.QuoteReport, error) {
+	query := `
+		SELECT id, quote_id, reason, comment, client_ip, user_agent, created_at, resolved_at, resolution, resolution_note
+		FROM quote_reports
+		WHERE quote_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.QuoteReport{}
+	for rows.Next() {
+		var report models.QuoteReport
+		var comment, userAgent, resolution, resolutionNote sql.NullString
+		var resolvedAt sql.NullTime
+		if err := rows.Scan(
+			&report.ID, &report.QuoteID, &report.Reason, &comment, &report.ClientIP, &userAgent,
+			&report.CreatedAt, &resolvedAt, &resolution, &resolutionNote,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		report.Comment = comment.String
+		report.UserAgent = userAgent.String
+		report.Resolution = resolution.String
+		report.ResolutionNote = resolutionNote.String
+		if resolvedAt.Valid {
+			report.ResolvedAt = &resolvedAt.Time
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// Resolve закрывает все открытые жалобы на цитату и применяет решение к цитате:
+// dismiss - цитата снова отображается, hide - цитата скрывается, delete - цитата перемещается в корзину
+// Возвращает количество закрытых жалоб
+func (r *reportRepository) Resolve(quoteID, action, note string) (int64, error) {
+	var quoteUpdate string
+	switch action {
+	case models.ResolutionDismiss:
+		quoteUpdate = `UPDATE quotes SET hidden_at = NULL WHERE id = $1`
+	case models.ResolutionHide:
+		quoteUpdate = `UPDATE quotes SET hidden_at = COALESCE(hidden_at, $1) WHERE id = $2`
+	case models.ResolutionDelete:
+		quoteUpdate = `UPDATE quotes SET deleted_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL`
+	default:
+		return 0, fmt.Errorf("invalid resolution action")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			// Игнорируем ошибку, если транзакция уже была закоммичена
+		}
+	}()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE quote_reports
+		SET resolved_at = $1, resolution = $2, resolution_note = $3
+		WHERE quote_id = $4 AND resolved_at IS NULL
+	`, now, action, nullString(note), quoteID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve reports: %w", err)
+	}
+
+	resolved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if resolved == 0 {
+		return 0, fmt.Errorf("no open reports")
+	}
+
+	if action == models.ResolutionDismiss {
+		_, err = tx.Exec(quoteUpdate, quoteID)
+	} else {
+		_, err = tx.Exec(quoteUpdate, now, quoteID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply resolution: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return resolved, nil
+}
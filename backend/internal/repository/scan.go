@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"quotes-backend/internal/models"
+)
+
+// quoteColumns содержит список колонок цитаты в порядке, ожидаемом scanQuote
+const quoteColumns = `id, text, author, likes_count,
+		source_title, source_year, source_page, source_url, source_context,
+		attribution_status, attribution_note,
+		status, rejection_reason, moderated_at, publish_at,
+		created_at, updated_at, deleted_at, version`
+
+// rowScanner абстрагирует *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanQuote считывает цитату из строки результата, выбранной по quoteColumns
+// Дополнительные колонки, выбранные после quoteColumns, считываются в extra
+func scanQuote(row rowScanner, quote *models.Quote, extra ...interface{}) error {
+	var (
+		sourceTitle, sourcePage, sourceURL, sourceContext sql.NullString
+		sourceYear                                        sql.NullInt64
+		attributionNote, rejectionReason                  sql.NullString
+		moderatedAt, publishAt, deletedAt                 sql.NullTime
+	)
+
+	dest := []interface{}{
+		&quote.ID,
+		&quote.Text,
+		&quote.Author,
+		&quote.LikesCount,
+		&sourceTitle,
+		&sourceYear,
+		&sourcePage,
+		&sourceURL,
+		&sourceContext,
+		&quote.AttributionStatus,
+		&attributionNote,
+		&quote.Status,
+		&rejectionReason,
+		&moderatedAt,
+		&publishAt,
+		&quote.CreatedAt,
+		&quote.UpdatedAt,
+		&deletedAt,
+		&quote.Version,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	source := &models.QuoteSource{
+		Title:   sourceTitle.String,
+		Page:    sourcePage.String,
+		URL:     sourceURL.String,
+		Context: sourceContext.String,
+	}
+	if sourceYear.Valid {
+		year := int(sourceYear.Int64)
+		source.Year = &year
+	}
+	if source.IsEmpty() {
+		source = nil
+	}
+	quote.Source = source
+	quote.AttributionNote = attributionNote.String
+	quote.RejectionReason = rejectionReason.String
+	quote.ModeratedAt = nil
+	if moderatedAt.Valid {
+		quote.ModeratedAt = &moderatedAt.Time
+	}
+	quote.PublishAt = nil
+	if publishAt.Valid {
+		quote.PublishAt = &publishAt.Time
+	}
+	quote.DeletedAt = nil
+	if deletedAt.Valid {
+		quote.DeletedAt = &deletedAt.Time
+	}
+
+	return nil
+}
+
+// sourceArgs возвращает значения колонок source_* для записи в базу данных
+// Пустые значения сохраняются как NULL
+func sourceArgs(source *models.QuoteSource) []interface{} {
+	if source.IsEmpty() {
+		return []interface{}{nil, nil, nil, nil, nil}
+	}
+
+	var year interface{}
+	if source.Year != nil {
+		year = *source.Year
+	}
+
+	return []interface{}{
+		nullString(source.Title),
+		year,
+		nullString(source.Page),
+		nullString(source.URL),
+		nullString(source.Context),
+	}
+}
+
+// nullTime преобразует необязательное время для записи в колонку TIMESTAMP
+// Время приводится к локальному часовому поясу, как и значения time.Now(), записываемые приложением
+func nullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Local()
+}
+
+// nullString преобразует пустую строку в NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -6,16 +6,21 @@ import (
 
 	"quotes-backend/internal/config"
 	"quotes-backend/internal/handlers"
+	"quotes-backend/internal/middleware"
+	"quotes-backend/internal/validation"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 // SetupRouter настраивает и возвращает роутер
-func SetupRouter(quoteHandler *handlers.QuoteHandler, cfg *config.Config) *gin.Engine {
+func SetupRouter(quoteHandler *handlers.QuoteHandler, authorHandler *handlers.AuthorHandler, reportHandler *handlers.ReportHandler, feedHandler *handlers.FeedHandler, shareHandler *handlers.ShareHandler, idempotencyStore middleware.IdempotencyStore, cfg *config.Config) *gin.Engine {
 	// Используем gin.New() вместо gin.Default() для production
 	// gin.Default() включает logger и recovery middleware, что замедляет
 	r := gin.New()
+
+	// Ошибки проверки полей ссылаются на имена полей JSON
+	validation.RegisterJSONFieldNames()
 	
 	// Добавляем только необходимый recovery middleware
 	r.Use(gin.Recovery())
@@ -37,32 +42,109 @@ func SetupRouter(quoteHandler *handlers.QuoteHandler, cfg *config.Config) *gin.E
 		corsConfig.AllowCredentials = true
 	}
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"}
-	corsConfig.ExposeHeaders = []string{"Content-Length", "Content-Type", "X-Backend"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-Editor", "If-Match", "If-None-Match", middleware.IdempotencyKeyHeader}
+	corsConfig.ExposeHeaders = []string{"Content-Length", "Content-Type", "X-Backend", "ETag", "Last-Modified", middleware.IdempotentReplayedHeader}
 	corsConfig.AllowBrowserExtensions = true
 
 	r.Use(cors.New(corsConfig))
 
+	// Отмечаем запросы администратора для публичных эндпоинтов
+	// (публикация без модерации, создание дубликата, отсутствие ограничения частоты)
+	r.Use(middleware.DetectAdmin(cfg.AdminPassword))
+
+	// Повтор запроса с тем же заголовком Idempotency-Key возвращает сохраненный ответ
+	idempotent := middleware.Idempotency(idempotencyStore, cfg.IdempotencyTTL)
+
+	// Валидаторы (ETag, 304) и Cache-Control для публичных GET эндпоинтов
+	cacheList := middleware.HTTPCache(cfg.CacheControlList, cfg.CacheControlPersonalized)
+	cacheQuote := middleware.HTTPCache(cfg.CacheControlQuote, cfg.CacheControlPersonalized)
+	cacheTop := middleware.HTTPCache(cfg.CacheControlTop, cfg.CacheControlPersonalized)
+
 	// API routes
-	api := r.Group("/api")
+	// Формат ответов (JSON, XML, текст, MessagePack) выбирается по заголовку Accept
+	api := r.Group("/api", middleware.Negotiate())
 	{
-		quotes := api.Group("/quotes")
+		// fields и include проверяются до обработчиков, чтобы ошибка в параметрах не выполняла запрос
+		// Роуты, ответы которых не состоят из цитат, отклоняют эти параметры
+		shape := middleware.Shape()
+		unshaped := middleware.Unshaped()
+		// Административные роуты требуют заголовок Authorization: Bearer <ADMIN_PASSWORD>
+		adminAuth := middleware.AdminAuth(cfg.AdminPassword)
+
+		quotes := api.Group("/quotes", shape)
 		{
 			// Специфичные роуты должны быть раньше параметризованных
 			quotes.GET("/random", quoteHandler.GetRandom)
-			quotes.GET("/top/weekly", quoteHandler.GetTopWeekly)
-			quotes.GET("/top/alltime", quoteHandler.GetTopAllTime)
+			quotes.GET("/top/weekly", cacheTop, quoteHandler.GetTopWeekly)
+			quotes.GET("/top/alltime", cacheTop, quoteHandler.GetTopAllTime)
+			quotes.GET("/batch", cacheList, quoteHandler.GetBatch)
+			quotes.GET("/likes", quoteHandler.GetLikes)
 			quotes.DELETE("/likes/reset", quoteHandler.ResetLikes)
-			quotes.GET("", quoteHandler.GetAll)
-			quotes.POST("", quoteHandler.Create)
+			quotes.GET("", cacheList, quoteHandler.GetAll)
+			// Посетители могут предлагать цитаты не чаще SUBMISSION_RATE_LIMIT раз за SUBMISSION_RATE_WINDOW
+			// Повторы по Idempotency-Key не учитываются в ограничении частоты
+			quotes.POST("", idempotent, middleware.RateLimit(cfg.SubmissionRateLimit, cfg.SubmissionRateWindow), quoteHandler.Create)
 			// Параметризованные роуты в конце
-			quotes.PUT("/:id/like", quoteHandler.Like)
-			quotes.GET("/:id", quoteHandler.GetByID)
-			quotes.PUT("/:id", quoteHandler.Update)
-			quotes.DELETE("/:id", quoteHandler.Delete)
+			quotes.PUT("/:id/like", idempotent, quoteHandler.Like)
+			quotes.POST("/:id/reports", middleware.RateLimit(cfg.ReportRateLimit, cfg.ReportRateWindow), reportHandler.Create)
+			quotes.GET("/:id/revisions", unshaped, quoteHandler.GetRevisions)
+			quotes.GET("/:id", cacheQuote, quoteHandler.GetByID)
+			// Изменять и удалять цитаты может только администратор
+			quotes.POST("/:id/revisions/:revisionId/restore", adminAuth, quoteHandler.RestoreRevision)
+			quotes.PUT("/:id", adminAuth, quoteHandler.Update)
+			quotes.PATCH("/:id", adminAuth, quoteHandler.Patch)
+			quotes.DELETE("/:id", adminAuth, quoteHandler.Delete)
+		}
+
+		authors := api.Group("/authors")
+		{
+			authors.GET("/aliases", authorHandler.GetAliases)
+		}
+
+		// У экспорта свой параметр include, поэтому форма ответа задается для каждого роута отдельно
+		admin := api.Group("/admin", adminAuth)
+		{
+			// Проверка пароля при входе в административную панель
+			admin.GET("/session", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			})
+			admin.GET("/trash", shape, quoteHandler.GetTrash)
+			admin.POST("/trash/:id/restore", shape, quoteHandler.RestoreFromTrash)
+			admin.DELETE("/trash/:id", quoteHandler.PurgeFromTrash)
+			admin.POST("/import", quoteHandler.Import)
+			admin.GET("/export", quoteHandler.Export)
+			admin.GET("/calendar", shape, quoteHandler.GetCalendar)
+			admin.GET("/moderation", shape, quoteHandler.GetModerationQueue)
+			admin.POST("/moderation/:id/approve", shape, quoteHandler.ApproveQuote)
+			admin.POST("/moderation/:id/reject", shape, quoteHandler.RejectQuote)
+			admin.GET("/reports", unshaped, reportHandler.GetFlagged)
+			admin.GET("/reports/:id", unshaped, reportHandler.GetReports)
+			admin.POST("/reports/:id/resolve", reportHandler.Resolve)
+			admin.GET("/duplicates", unshaped, quoteHandler.GetDuplicates)
+			admin.POST("/duplicates/merge", shape, quoteHandler.MergeDuplicates)
+			admin.POST("/typography/preview", quoteHandler.PreviewTypography)
+			admin.POST("/authors/aliases", authorHandler.CreateAlias)
+			admin.DELETE("/authors/aliases/:id", authorHandler.DeleteAlias)
 		}
 	}
 
+	// Ленты RSS и Atom, календарь цитат дня и карта сайта одинаковы для всех клиентов
+	cacheFeed := middleware.HTTPCache(cfg.CacheControlFeed, cfg.CacheControlFeed)
+	feeds := r.Group("/feeds", cacheFeed)
+	{
+		feeds.GET("/quotes.rss", feedHandler.GetLatestRSS)
+		feeds.GET("/quotes.atom", feedHandler.GetLatestAtom)
+		feeds.GET("/top-weekly.rss", feedHandler.GetTopWeeklyRSS)
+		feeds.GET("/top-weekly.atom", feedHandler.GetTopWeeklyAtom)
+		feeds.GET("/quotes-of-the-day.ics", feedHandler.GetDailyCalendar)
+	}
+
+	// Страницы цитат для превью ссылок в социальных сетях и карта сайта
+	// Страница карты сайта ограничена по размеру, поэтому ответ буферизуется HTTPCache, как ленты
+	cacheShare := middleware.HTTPCache(cfg.CacheControlQuote, cfg.CacheControlQuote)
+	r.GET("/q/:id", cacheShare, shareHandler.GetSharePage)
+	r.GET("/sitemap.xml", cacheFeed, shareHandler.GetSitemap)
+	r.GET("/sitemap-quotes.xml", cacheFeed, shareHandler.GetSitemapPage)
 
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
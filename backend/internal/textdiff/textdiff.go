@@ -0,0 +1,133 @@
+package textdiff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Типы операций различий
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Op представляет один фрагмент различий между двумя текстами
+type Op struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// maxDiffCells ограничивает размер таблицы общей подпоследовательности (произведение количества токенов)
+// Если отличающаяся часть текстов больше, она возвращается целиком как удаление и вставка
+const maxDiffCells = 1 << 18
+
+// Diff вычисляет пословное различие между старым и новым текстом
+// Общие начало и конец текстов отбрасываются, для оставшейся части используется алгоритм наибольшей
+// общей подпоследовательности по токенам (слова и пробелы); таблица алгоритма ограничена maxDiffCells
+func Diff(oldText, newText string) []Op {
+	a := tokenize(oldText)
+	b := tokenize(newText)
+
+	var ops []Op
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		ops = appendOp(ops, OpEqual, a[prefix])
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops = diffTokens(ops, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	for _, token := range a[len(a)-suffix:] {
+		ops = appendOp(ops, OpEqual, token)
+	}
+
+	return ops
+}
+
+// diffTokens добавляет к ops различия между последовательностями токенов a и b
+func diffTokens(ops []Op, a, b []string) []Op {
+	if len(a)*len(b) > maxDiffCells {
+		ops = appendOp(ops, OpDelete, strings.Join(a, ""))
+		return appendOp(ops, OpInsert, strings.Join(b, ""))
+	}
+
+	// lcs[i][j] - длина общей подпоследовательности для a[i:] и b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = appendOp(ops, OpEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = appendOp(ops, OpDelete, a[i])
+			i++
+		default:
+			ops = appendOp(ops, OpInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = appendOp(ops, OpDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		ops = appendOp(ops, OpInsert, b[j])
+	}
+
+	return ops
+}
+
+// appendOp добавляет токен к результату, объединяя соседние операции одного типа
+func appendOp(ops []Op, opType, text string) []Op {
+	if text == "" {
+		return ops
+	}
+	if n := len(ops); n > 0 && ops[n-1].Type == opType {
+		ops[n-1].Text += text
+		return ops
+	}
+	return append(ops, Op{Type: opType, Text: text})
+}
+
+// tokenize разбивает текст на слова и последовательности пробельных символов
+func tokenize(s string) []string {
+	var tokens []string
+	var b strings.Builder
+	inSpace := false
+
+	for _, r := range s {
+		isSpace := unicode.IsSpace(r)
+		if b.Len() > 0 && isSpace != inSpace {
+			tokens = append(tokens, b.String())
+			b.Reset()
+		}
+		inSpace = isSpace
+		b.WriteRune(r)
+	}
+	if b.Len() > 0 {
+		tokens = append(tokens, b.String())
+	}
+
+	return tokens
+}
@@ -0,0 +1,173 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cyrToLat содержит правила транслитерации кириллицы в латиницу
+var cyrToLat = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// latDigraphs содержит многобуквенные сочетания латиницы, проверяемые раньше одиночных букв
+// Порядок важен: более длинные сочетания должны идти первыми
+var latDigraphs = []struct {
+	lat string
+	cyr string
+}{
+	{"shch", "щ"},
+	{"sch", "щ"},
+	{"zh", "ж"},
+	{"kh", "х"},
+	{"ts", "ц"},
+	{"ch", "ч"},
+	{"sh", "ш"},
+	{"yu", "ю"},
+	{"ya", "я"},
+	{"yo", "ё"},
+	{"ye", "е"},
+	{"ph", "ф"},
+}
+
+// latToCyr содержит правила транслитерации одиночных латинских букв
+var latToCyr = map[rune]string{
+	'a': "а", 'b': "б", 'c': "к", 'd': "д", 'e': "е", 'f': "ф", 'g': "г",
+	'h': "х", 'i': "и", 'j': "дж", 'k': "к", 'l': "л", 'm': "м", 'n': "н",
+	'o': "о", 'p': "п", 'q': "к", 'r': "р", 's': "с", 't': "т", 'u': "у",
+	'v': "в", 'w': "в", 'x': "кс", 'z': "з",
+}
+
+// ToLatin транслитерирует кириллицу в латиницу, остальные символы оставляет как есть
+func ToLatin(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range s {
+		lower := unicode.ToLower(r)
+		lat, ok := cyrToLat[lower]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		if lower != r {
+			lat = capitalize(lat)
+		}
+		b.WriteString(lat)
+	}
+
+	return b.String()
+}
+
+// ToCyrillic транслитерирует латиницу в кириллицу, остальные символы оставляет как есть
+// Обратная транслитерация неоднозначна, поэтому результат подходит для поиска,
+// но не для отображения (для точных соответствий используются псевдонимы авторов)
+func ToCyrillic(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		upper := unicode.IsUpper(r)
+
+		if cyr, n := matchDigraph(runes[i:]); n > 0 {
+			if upper {
+				cyr = capitalize(cyr)
+			}
+			b.WriteString(cyr)
+			i += n
+			continue
+		}
+
+		lower := unicode.ToLower(r)
+		var cyr string
+		switch {
+		case lower == 'y':
+			// "y" после гласной обычно означает "й" (Tolstoy), иначе "ы"/"и"
+			if i > 0 && isLatinVowel(runes[i-1]) {
+				cyr = "й"
+			} else {
+				cyr = "и"
+			}
+		default:
+			var ok bool
+			cyr, ok = latToCyr[lower]
+			if !ok {
+				b.WriteRune(r)
+				i++
+				continue
+			}
+		}
+
+		if upper {
+			cyr = capitalize(cyr)
+		}
+		b.WriteString(cyr)
+		i++
+	}
+
+	return b.String()
+}
+
+// Variants возвращает набор вариантов написания строки: исходный, латиницей и кириллицей
+// Дубликаты исключаются, исходная строка всегда идет первой
+func Variants(s string) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	variants := []string{s}
+	for _, v := range []string{ToLatin(s), ToCyrillic(s)} {
+		if !containsFold(variants, v) {
+			variants = append(variants, v)
+		}
+	}
+
+	return variants
+}
+
+// matchDigraph ищет многобуквенное сочетание в начале последовательности
+// Возвращает кириллический эквивалент и количество поглощенных символов
+func matchDigraph(runes []rune) (string, int) {
+	for _, d := range latDigraphs {
+		n := len(d.lat)
+		if len(runes) < n {
+			continue
+		}
+		if strings.EqualFold(string(runes[:n]), d.lat) {
+			return d.cyr, n
+		}
+	}
+	return "", 0
+}
+
+// isLatinVowel проверяет, является ли символ латинской гласной
+func isLatinVowel(r rune) bool {
+	return strings.ContainsRune("aeiouAEIOU", r)
+}
+
+// capitalize переводит первую букву строки в верхний регистр
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// containsFold проверяет наличие строки в списке без учета регистра
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,267 @@
+// Package typography приводит типографику русских и английских цитат к единому виду:
+// кавычки, тире, многоточия, неразрывные пробелы и (по желанию) буква ё
+package typography
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"quotes-backend/internal/models"
+)
+
+// Языки, для которых различаются правила расстановки кавычек
+const (
+	LanguageRussian = "ru"
+	LanguageEnglish = "en"
+)
+
+// nbsp - неразрывный пробел
+const nbsp = '\u00a0'
+
+// Options - параметры нормализации
+type Options struct {
+	Language  string // ru или en; пустое значение - язык определяется по тексту (см. DetectLanguage)
+	RestoreYo bool   // Восстанавливать ё в словах, где е однозначно означает ё (еще -> ещё)
+}
+
+// quoteStyle - внешние и вложенные кавычки языка
+type quoteStyle struct {
+	open, close, innerOpen, innerClose rune
+}
+
+var quoteStyles = map[string]quoteStyle{
+	LanguageRussian: {open: '«', close: '»', innerOpen: '„', innerClose: '“'},
+	LanguageEnglish: {open: '“', close: '”', innerOpen: '‘', innerClose: '’'},
+}
+
+var (
+	ellipsisPattern = regexp.MustCompile(`\.{3}`)
+	spacesPattern   = regexp.MustCompile(` {2,}`)
+	// Дефис или тире, окруженные пробелами: "слово - слово", "слово -- слово", "слово – слово"
+	spacedDashPattern = regexp.MustCompile(`[ \t\x{00a0}]+(?:-{1,2}|–|—)[ \t\x{00a0}]+`)
+	// Дефис в начале строки (прямая речь): "- Привет"
+	leadingDashPattern  = regexp.MustCompile(`(?m)^(?:-{1,2}|–)[ \t]+`)
+	doubleHyphenPattern = regexp.MustCompile(`(\pL)--(\pL)`)
+	// Диапазон чисел: "1812-1815" -> "1812–1815"
+	rangePattern = regexp.MustCompile(`(^|[^\d-])(\d{1,4})-(\d{1,4})($|[^\d-])`)
+	wordPattern  = regexp.MustCompile(`[А-Яа-яЁё]+(?:-[А-Яа-яЁё]+)*`)
+)
+
+// DetectLanguage определяет язык текста по преобладающему алфавиту
+func DetectLanguage(s string) string {
+	cyrillic, latin := 0, 0
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if latin > cyrillic {
+		return LanguageEnglish
+	}
+	return LanguageRussian
+}
+
+// Normalize приводит типографику текста к правилам языка
+func Normalize(s string, opts Options) string {
+	language := opts.Language
+	if _, ok := quoteStyles[language]; !ok {
+		language = DetectLanguage(s)
+	}
+
+	s = spacesPattern.ReplaceAllString(s, " ")
+	s = ellipsisPattern.ReplaceAllString(s, "…")
+	s = leadingDashPattern.ReplaceAllString(s, "— ")
+	s = spacedDashPattern.ReplaceAllString(s, string(nbsp)+"— ")
+	s = doubleHyphenPattern.ReplaceAllString(s, "$1—$2")
+	// Соседние диапазоны разделяют одну границу, поэтому замена выполняется дважды
+	s = rangePattern.ReplaceAllString(s, "$1$2–$3$4")
+	s = rangePattern.ReplaceAllString(s, "$1$2–$3$4")
+	s = replaceQuotes(s, quoteStyles[language], language)
+
+	if opts.RestoreYo && language == LanguageRussian {
+		s = restoreYo(s)
+	}
+
+	return s
+}
+
+// Quote нормализует типографику текстовых полей цитаты
+// Язык определяется по тексту цитаты и применяется ко всем полям; автор и ссылка на источник не изменяются
+func Quote(quote *models.Quote, opts Options) {
+	if opts.Language == "" {
+		opts.Language = DetectLanguage(quote.Text)
+	}
+
+	quote.Text = Normalize(quote.Text, opts)
+	quote.AttributionNote = Normalize(quote.AttributionNote, opts)
+	if quote.Source != nil {
+		quote.Source.Title = Normalize(quote.Source.Title, opts)
+		quote.Source.Context = Normalize(quote.Source.Context, opts)
+	}
+}
+
+// replaceQuotes расставляет парные кавычки с учетом вложенности
+// Направление прямых кавычек определяется по соседним символам
+func replaceQuotes(s string, style quoteStyle, language string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s))
+
+	depth := 0
+	for i, r := range runes {
+		prev, next := rune(0), rune(0)
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch r {
+		case '"', '“', '”', '«', '»', '„':
+			opening := r == '«' || r == '„'
+			if r != '«' && r != '»' && r != '„' {
+				opening = isOpeningPosition(prev, next)
+				if opening && (next == 0 || unicode.IsSpace(next)) {
+					// Кавычка окружена пробелами - направление не определить
+					b.WriteRune(r)
+					continue
+				}
+			}
+
+			if opening {
+				if depth == 0 {
+					b.WriteRune(style.open)
+				} else {
+					b.WriteRune(style.innerOpen)
+				}
+				depth++
+				continue
+			}
+
+			if depth > 1 {
+				b.WriteRune(style.innerClose)
+			} else {
+				b.WriteRune(style.close)
+			}
+			if depth > 0 {
+				depth--
+			}
+		case '\'':
+			switch {
+			case unicode.IsLetter(prev) && unicode.IsLetter(next):
+				// Апостроф внутри слова: don't, д'Артаньян
+				b.WriteRune('’')
+			case language == LanguageEnglish && isOpeningPosition(prev, next) && !unicode.IsSpace(next) && next != 0:
+				b.WriteRune('‘')
+			case language == LanguageEnglish && prev != 0 && !unicode.IsSpace(prev):
+				b.WriteRune('’')
+			default:
+				b.WriteRune(r)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// isOpeningPosition проверяет, что кавычка стоит в начале слова
+func isOpeningPosition(prev, next rune) bool {
+	if prev == 0 || unicode.IsSpace(prev) {
+		return true
+	}
+	return strings.ContainsRune("([{«„“‘—–-", prev)
+}
+
+// yoWords - слова, в которых е однозначно означает ё
+// Неоднозначные пары (все/всё, небо/нёбо, узнает/узнаёт, моем/моём) не включены
+var yoWords = map[string]string{
+	"еще":      "ещё",
+	"ее":       "её",
+	"нее":      "неё",
+	"причем":   "причём",
+	"черный":   "чёрный",
+	"черная":   "чёрная",
+	"черное":   "чёрное",
+	"черные":   "чёрные",
+	"желтый":   "жёлтый",
+	"зеленый":  "зелёный",
+	"темный":   "тёмный",
+	"легкий":   "лёгкий",
+	"мертвый":  "мёртвый",
+	"мертвые":  "мёртвые",
+	"звезды":   "звёзды",
+	"слезы":    "слёзы",
+	"шел":      "шёл",
+	"пришел":   "пришёл",
+	"ушел":     "ушёл",
+	"нашел":    "нашёл",
+	"прошел":   "прошёл",
+	"вошел":    "вошёл",
+	"пошел":    "пошёл",
+	"твоем":    "твоём",
+	"своем":    "своём",
+	"живем":    "живём",
+	"идем":     "идём",
+	"идет":     "идёт",
+	"идешь":    "идёшь",
+	"поет":     "поёт",
+	"дает":     "даёт",
+	"дается":   "даётся",
+	"встает":   "встаёт",
+	"остается": "остаётся",
+	"ведет":    "ведёт",
+	"несет":    "несёт",
+	"растет":   "растёт",
+	"живет":    "живёт",
+	"зовет":    "зовёт",
+	"ждет":     "ждёт",
+	"трех":     "трёх",
+	"четырех":  "четырёх",
+	"елка":     "ёлка",
+	"все-таки": "всё-таки",
+	"самолет":  "самолёт",
+	"полет":    "полёт",
+	"счет":     "счёт",
+	"расчет":   "расчёт",
+	"лед":      "лёд",
+	"клен":     "клён",
+	"ребенок":  "ребёнок",
+	"веселый":  "весёлый",
+	"тяжелый":  "тяжёлый",
+	"учеба":    "учёба",
+	"ученый":   "учёный",
+	"ученые":   "учёные",
+}
+
+// restoreYo заменяет е на ё в словах из словаря, сохраняя регистр
+func restoreYo(s string) string {
+	return wordPattern.ReplaceAllStringFunc(s, func(word string) string {
+		replacement, ok := yoWords[strings.ToLower(word)]
+		if !ok {
+			return word
+		}
+		return matchCase(word, replacement)
+	})
+}
+
+// matchCase переносит регистр букв word на replacement (слова одинаковой длины)
+func matchCase(word, replacement string) string {
+	source := []rune(word)
+	target := []rune(replacement)
+	if len(source) != len(target) {
+		return word
+	}
+	for i, r := range source {
+		if unicode.IsUpper(r) {
+			target[i] = unicode.ToUpper(target[i])
+		}
+	}
+	return string(target)
+}
@@ -0,0 +1,179 @@
+// Package validation нормализует и проверяет текстовые поля цитат перед сохранением
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"quotes-backend/internal/models"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Ограничения длины по умолчанию (в символах)
+// Автор хранится в VARCHAR(255), поэтому MaxAuthorLength не может быть больше
+const (
+	DefaultMaxTextLength   = 2000
+	DefaultMaxAuthorLength = 255
+
+	maxNoteLength        = 2000
+	maxSourceTitleLength = 500
+	maxSourceURLLength   = 2000
+	maxSourceContext     = 5000
+)
+
+// Limits - настраиваемые ограничения длины текста и автора цитаты (0 - значение по умолчанию)
+type Limits struct {
+	MaxTextLength   int
+	MaxAuthorLength int
+}
+
+// Errors - ошибки проверки отдельных полей
+type Errors []models.FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *Errors) add(field, message string) {
+	*e = append(*e, models.FieldError{Field: field, Message: message})
+}
+
+// Quote нормализует редактируемые поля цитаты и проверяет их
+// Строки приводятся к NFC, пробелы по краям удаляются, переводы строк приводятся к \n
+// Возвращает Errors, если хотя бы одно поле некорректно
+func (l Limits) Quote(quote *models.Quote) error {
+	maxText := l.MaxTextLength
+	if maxText <= 0 {
+		maxText = DefaultMaxTextLength
+	}
+	maxAuthor := l.MaxAuthorLength
+	if maxAuthor <= 0 || maxAuthor > DefaultMaxAuthorLength {
+		maxAuthor = DefaultMaxAuthorLength
+	}
+
+	var errs Errors
+	quote.Text = checkField(&errs, "text", quote.Text, true, true, maxText)
+	quote.Author = checkField(&errs, "author", quote.Author, false, true, maxAuthor)
+	quote.AttributionNote = checkField(&errs, "attribution_note", quote.AttributionNote, true, false, maxNoteLength)
+
+	if source := quote.Source; source != nil {
+		source.Title = checkField(&errs, "source.title", source.Title, false, false, maxSourceTitleLength)
+		source.Page = checkField(&errs, "source.page", source.Page, false, false, 50)
+		source.URL = checkField(&errs, "source.url", source.URL, false, false, maxSourceURLLength)
+		source.Context = checkField(&errs, "source.context", source.Context, true, false, maxSourceContext)
+		if source.IsEmpty() {
+			quote.Source = nil
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Normalize приводит строку к NFC, заменяет переводы строк \r\n и \r на \n и удаляет пробелы по краям
+func Normalize(s string) string {
+	s = norm.NFC.String(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return strings.TrimSpace(s)
+}
+
+// checkField нормализует значение поля и добавляет в errs найденные ошибки
+// multiline разрешает переводы строк и табуляцию, остальные управляющие символы запрещены всегда
+func checkField(errs *Errors, field, value string, multiline, required bool, maxLength int) string {
+	if !utf8.ValidString(value) {
+		errs.add(field, "must be valid UTF-8")
+		return value
+	}
+	value = Normalize(value)
+
+	if value == "" {
+		if required {
+			errs.add(field, "is required")
+		}
+		return value
+	}
+	if length := utf8.RuneCountInString(value); length > maxLength {
+		errs.add(field, fmt.Sprintf("must be at most %d characters, got %d", maxLength, length))
+	}
+	for _, r := range value {
+		if multiline && (r == '\n' || r == '\t') {
+			continue
+		}
+		if unicode.IsControl(r) {
+			errs.add(field, fmt.Sprintf("must not contain control character %U", r))
+			break
+		}
+	}
+
+	return value
+}
+
+// FromBinding преобразует ошибку проверки gin binding в ошибки полей
+// Возвращает false для ошибок, не связанных с проверкой полей (например, некорректный JSON)
+func FromBinding(err error) (Errors, bool) {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil, false
+	}
+
+	errs := make(Errors, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		message := "failed on " + fieldErr.Tag()
+		switch fieldErr.Tag() {
+		case "required":
+			message = "is required"
+		case "oneof":
+			message = "must be one of: " + fieldErr.Param()
+		case "max":
+			message = "must be at most " + fieldErr.Param()
+		case "min":
+			message = "must be at least " + fieldErr.Param()
+		case "url":
+			message = "must be a valid URL"
+		}
+		errs.add(fieldPath(fieldErr), message)
+	}
+	return errs, true
+}
+
+// fieldPath возвращает путь к полю без имени корневой структуры: "source.url"
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// RegisterJSONFieldNames настраивает валидатор gin на имена полей из тегов json,
+// чтобы ошибки ссылались на поля запроса (attribution_status), а не структуры (AttributionStatus)
+func RegisterJSONFieldNames() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
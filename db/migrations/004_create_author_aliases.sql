@@ -0,0 +1,29 @@
+-- Создание таблицы псевдонимов авторов
+-- Позволяет находить одного и того же автора по разным написаниям имени
+-- (например, "Стив Джобс", "Steve Jobs" и "Jobs")
+CREATE TABLE IF NOT EXISTS author_aliases (
+    id VARCHAR(36) PRIMARY KEY,
+    author VARCHAR(255) NOT NULL,
+    alias VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(author, alias)
+);
+
+-- Создание индексов для поиска по псевдонимам
+CREATE INDEX IF NOT EXISTS idx_author_aliases_author ON author_aliases(author);
+CREATE INDEX IF NOT EXISTS idx_author_aliases_alias ON author_aliases(LOWER(alias));
+
+-- Псевдонимы для авторов из тестовых данных
+INSERT INTO author_aliases (id, author, alias) VALUES
+('7d1e0c2a-4f6b-4d8e-9a10-000000000001', 'Стив Джобс', 'Steve Jobs'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000002', 'Стив Джобс', 'Jobs'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000003', 'Леонардо да Винчи', 'Leonardo da Vinci'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000004', 'Джон Леннон', 'John Lennon'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000005', 'Уинстон Черчилль', 'Winston Churchill'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000006', 'Элеонора Рузвельт', 'Eleanor Roosevelt'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000007', 'Ральф Уолдо Эмерсон', 'Ralph Waldo Emerson'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000008', 'Конфуций', 'Confucius'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000009', 'Марк Твен', 'Mark Twain'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000010', 'Аристотель', 'Aristotle'),
+('7d1e0c2a-4f6b-4d8e-9a10-000000000011', 'Альберт Эйнштейн', 'Albert Einstein')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,24 @@
+-- Добавление сведений об источнике цитаты
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS source_title TEXT;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS source_year INTEGER;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS source_page VARCHAR(50);
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS source_url TEXT;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS source_context TEXT;
+
+-- Добавление статуса атрибуции (verified, disputed, misattributed, unknown)
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS attribution_status VARCHAR(20) NOT NULL DEFAULT 'unknown';
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS attribution_note TEXT;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'quotes_attribution_status_check'
+    ) THEN
+        ALTER TABLE quotes ADD CONSTRAINT quotes_attribution_status_check
+            CHECK (attribution_status IN ('verified', 'disputed', 'misattributed', 'unknown'));
+    END IF;
+END $$;
+
+-- Создание индекса для фильтрации по статусу атрибуции
+CREATE INDEX IF NOT EXISTS idx_quotes_attribution_status ON quotes(attribution_status);
@@ -0,0 +1,14 @@
+-- Создание таблицы истории изменений цитат
+-- old_data и new_data содержат снимки редактируемых полей цитаты в JSON
+-- old_data пустой для ревизии, созданной вместе с цитатой
+CREATE TABLE IF NOT EXISTS quote_revisions (
+    id VARCHAR(36) PRIMARY KEY,
+    quote_id VARCHAR(36) NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    old_data JSONB,
+    new_data JSONB NOT NULL,
+    editor VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Создание индекса для получения истории цитаты
+CREATE INDEX IF NOT EXISTS idx_quote_revisions_quote_id ON quote_revisions(quote_id, created_at DESC);
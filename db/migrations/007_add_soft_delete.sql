@@ -0,0 +1,6 @@
+-- Добавление мягкого удаления цитат (корзина)
+-- Цитаты с заполненным deleted_at скрыты из всех списков, но сохраняют лайки и историю
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Создание индекса для работы с корзиной и автоматической очистки
+CREATE INDEX IF NOT EXISTS idx_quotes_deleted_at ON quotes(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Поиск дубликатов цитат
+-- content_hash - хеш нормализованного текста (без регистра, пунктуации и лишних пробелов),
+-- вычисляется приложением; существующие цитаты заполняются при запуске сервера
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_quotes_content_hash ON quotes(content_hash) WHERE deleted_at IS NULL;
+
+-- Поиск похожих цитат по триграммам
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_quotes_text_trgm ON quotes USING gin (lower(text) gin_trgm_ops);
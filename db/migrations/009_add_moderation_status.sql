@@ -0,0 +1,8 @@
+-- Модерация предложенных посетителями цитат
+-- Существующие цитаты считаются одобренными; в списках отображаются только цитаты со статусом approved
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'approved';
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+
+-- Создание индекса для очереди модерации
+CREATE INDEX IF NOT EXISTS idx_quotes_status ON quotes(status, created_at) WHERE status <> 'approved';
@@ -0,0 +1,21 @@
+-- Создание таблицы жалоб на цитаты
+-- Открытые жалобы (resolved_at IS NULL) учитываются в отчете администратора и для автоматического скрытия
+CREATE TABLE IF NOT EXISTS quote_reports (
+    id VARCHAR(36) PRIMARY KEY,
+    quote_id VARCHAR(36) NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    reason VARCHAR(30) NOT NULL,
+    comment TEXT,
+    client_ip VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    resolution VARCHAR(20),
+    resolution_note TEXT
+);
+
+-- Один клиент может оставить только одну открытую жалобу на цитату
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quote_reports_open_client ON quote_reports(quote_id, client_ip) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_quote_reports_quote_id ON quote_reports(quote_id, created_at DESC);
+
+-- Цитаты, скрытые по жалобам до решения администратора
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP;
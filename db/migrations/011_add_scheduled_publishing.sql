@@ -0,0 +1,6 @@
+-- Черновики и отложенная публикация цитат
+-- Черновики (status = 'draft') и цитаты с publish_at в будущем не отображаются в публичных списках
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+
+-- Создание индекса для календаря публикаций
+CREATE INDEX IF NOT EXISTS idx_quotes_publish_at ON quotes(publish_at) WHERE publish_at IS NOT NULL;
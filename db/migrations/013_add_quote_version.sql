@@ -0,0 +1,3 @@
+-- Версия цитаты для оптимистичной блокировки (ETag / If-Match)
+-- Увеличивается при каждом изменении цитаты
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,10 @@
+-- Создание таблицы цитат дня
+-- Цитата закрепляется за датой при первом запросе, поэтому календарь (.ics) стабилен между запросами
+CREATE TABLE IF NOT EXISTS daily_quotes (
+    day DATE PRIMARY KEY,
+    quote_id VARCHAR(36) NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Создание индекса для выбора давно не показанных цитат
+CREATE INDEX IF NOT EXISTS idx_daily_quotes_quote_id ON daily_quotes(quote_id, day DESC);
@@ -0,0 +1,3 @@
+-- Удаление IP адресов посетителей из истории ревизий
+-- История ревизий публична, поэтому изменения посетителей записываются от имени anonymous
+UPDATE quote_revisions SET editor = 'anonymous' WHERE editor LIKE 'ip:%';
@@ -49,6 +49,11 @@ export interface UpdateQuoteRequest {
   author?: string
 }
 
+export interface BatchQuotesResponse {
+  quotes: Quote[]
+  missing: string[] // ID удаленных или несуществующих цитат
+}
+
 export interface PaginatedQuotesResponse {
   quotes: Quote[]
   total: number
@@ -84,6 +89,14 @@ export const quotesApi = {
     return response.data
   },
 
+  // Получить несколько цитат по ID одним запросом (не больше 100)
+  getBatch: async (ids: string[]): Promise<BatchQuotesResponse> => {
+    const response = await apiClient.get<BatchQuotesResponse>('/quotes/batch', {
+      params: { ids: ids.join(',') },
+    })
+    return response.data
+  },
+
   // Создать новую цитату
   create: async (data: CreateQuoteRequest): Promise<Quote> => {
     const response = await apiClient.post<Quote>('/quotes', data)